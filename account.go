@@ -3,7 +3,6 @@ package tbdb
 import (
 	"errors"
 	"fmt"
-	"log"
 
 	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
 )
@@ -24,6 +23,10 @@ const (
 	AccountCategoryIncome AccountCategory = 1002
 	// Account category used for account that is liabilities account.
 	AccountCategoryLiabilities AccountCategory = 1003
+	// Account category used for account that is equity account & respected 'DebitsMustNotExceedCredits' flags.
+	AccountCategoryEquity AccountCategory = 1004
+	// Account category used for account that is expense account & respected 'CreditsMustNotExceedDebits' flags.
+	AccountCategoryExpense AccountCategory = 1005
 	// Account category used for account that is testing account.
 	AccountCategoryTesting AccountCategory = 9999
 )
@@ -93,7 +96,7 @@ func (i *Instance) CreateAccountBatch(accounts []CreateAccounts) (AccountEventRe
 	for idx, account := range accounts {
 		var id Uint128
 		if account.ID.IsZero() {
-			id = NewID()
+			id = i.newID()
 		} else {
 			id = account.ID
 		}
@@ -186,31 +189,43 @@ func (i *Instance) CreateAccount(account CreateAccount, code uint16, flags Accou
 	return result.Results[0], nil
 }
 
-// CreateAccountsWithCategory creates TigerBeetle's account with built-in category from this tbdb package.
-// It will return 'ErrUnknownCategory' if given category is unknown.
-// Some built-in category have their respected account flags, as shown on the list bellow:
+// Flags returns the account flags of the built-in category, as shown on the list bellow.
+// It will return 'ErrUnknownCategory' if category is unknown.
 //
 //   - AccountCategoryControl: History
 //   - AccountCategoryBalance: History, DebitsMustNotExceedCredits
 //   - AccountCategoryIncome: History, DebitsMustNotExceedCredits
+//   - AccountCategoryLiabilities: History
+//   - AccountCategoryEquity: History, DebitsMustNotExceedCredits
+//   - AccountCategoryExpense: History, CreditsMustNotExceedDebits
 //   - AccountCategoryTesting: None
+func (c AccountCategory) Flags() (AccountFlags, error) {
+	switch c {
+	case AccountCategoryControl, AccountCategoryLiabilities:
+		return AccountFlags{History: true}, nil
+	case AccountCategoryBalance, AccountCategoryIncome, AccountCategoryEquity:
+		return AccountFlags{History: true, DebitsMustNotExceedCredits: true}, nil
+	case AccountCategoryExpense:
+		return AccountFlags{History: true, CreditsMustNotExceedDebits: true}, nil
+	case AccountCategoryTesting:
+		return AccountFlags{}, nil
+	}
+	return AccountFlags{}, ErrUnknownCategory
+}
+
+// CreateAccountsWithCategory creates TigerBeetle's account with built-in category from this tbdb package.
+// It will return 'ErrUnknownCategory' if given category is unknown.
+// Built-in category have their respected account flags, see AccountCategory.Flags.
 func (i *Instance) CreateAccountsWithCategory(category AccountCategory, accounts ...CreateAccount) (AccountEventResults, error) {
 	// Validate.
 	if len(accounts) == 0 {
 		return AccountEventResults{}, errors.New("at least give 1 account")
 	}
-	if category != AccountCategoryControl && category != AccountCategoryBalance && category != AccountCategoryIncome &&
-		category != AccountCategoryLiabilities && category != AccountCategoryTesting {
-		return AccountEventResults{}, ErrUnknownCategory
-	}
 
 	// Set proper account flags based on category.
-	flags := AccountFlags{History: true}
-	switch category {
-	case AccountCategoryBalance, AccountCategoryIncome:
-		flags.DebitsMustNotExceedCredits = true
-	case AccountCategoryTesting:
-		flags.History = false
+	flags, err := category.Flags()
+	if err != nil {
+		return AccountEventResults{}, err
 	}
 
 	// Create single account.
@@ -284,11 +299,31 @@ type AccountLookup struct {
 	// ID is unique account identifier.
 	ID Uint128
 	// Monetary represent account monetary type.
+	// Optional; if nil, it is resolved from the account ledger with ParseLedgerCode.
 	Monetary Amount
+	// Ledger is the expected account ledger.
+	// Optional; if set, an account on another ledger is reported with ErrLedgerMismatch.
+	Ledger Ledger
 }
 
-// LookupAccounts fetchs one or more accounts by their ids alongside the monetary.
-func (i *Instance) LookupAccounts(lookups []AccountLookup) ([]Account, error) {
+// AccountLookupResult defines result data for single account lookup.
+type AccountLookupResult struct {
+	// Index of the lookup in the original request.
+	Index uint32
+	// ID is the looked up account identifier.
+	ID Uint128
+	// Found reports whether the account exists, Account is zero if not.
+	Found bool
+	// Account is the looked up account.
+	Account Account
+	// Err is the lookup error, e.g. ErrCurrencyAmountMismatch or ErrLedgerMismatch.
+	Err error
+}
+
+// LookupAccountsOrdered fetchs one or more accounts by their ids alongside the monetary.
+// Returns a result for every lookup in the request order, accounts that do not exist are marked as not found.
+// Monetary of another currency than the account ledger and unexpected ledgers are reported per result.
+func (i *Instance) LookupAccountsOrdered(lookups []AccountLookup) ([]AccountLookupResult, error) {
 	// Validate.
 	countLookup := len(lookups)
 	if countLookup > int(TigerBeetleMaxBatch) {
@@ -300,59 +335,148 @@ func (i *Instance) LookupAccounts(lookups []AccountLookup) ([]Account, error) {
 		return nil, err
 	}
 
-	// Uint128 to TigerBeetle's Uint128.
-	mapMonetary := make(map[string]Amount, countLookup)
-	tbIds := make([]types.Uint128, 0, len(lookups))
+	// Uint128 to TigerBeetle's Uint128, duplicate ids are looked up once.
+	tbIds := make([]types.Uint128, 0, countLookup)
+	seen := make(map[Uint128]struct{}, countLookup)
 	for _, lookup := range lookups {
+		if _, ok := seen[lookup.ID]; ok {
+			continue
+		}
+		seen[lookup.ID] = struct{}{}
 		tbIds = append(tbIds, toBinding(lookup.ID))
-		mapMonetary[lookup.ID.String()] = lookup.Monetary
 	}
-	defer func() {
-		mapMonetary = nil
-		tbIds = nil
-	}()
 
 	// Perform TigerBeetle LookupAccounts.
 	tbAccounts, err := cln.LookupAccounts(tbIds)
 	if err != nil {
 		return nil, err
 	}
-	defer func() { tbAccounts = nil }()
-
-	// Convert TigerBeetle's Account to Account.
-	accounts := make([]Account, 0, len(tbAccounts))
+	found := make(map[Uint128]types.Account, len(tbAccounts))
 	for _, account := range tbAccounts {
-		// Get monetary.
-		monetary, exists := mapMonetary[account.ID.String()]
-		if !exists {
-			log.Printf("[tbdb] Warning: monetary map for %s does not exists", account.ID.String())
+		found[fromBinding(account.ID)] = account
+	}
+
+	// Convert TigerBeetle's Account to Account in the request order.
+	results := make([]AccountLookupResult, countLookup)
+	for idx, lookup := range lookups {
+		results[idx] = AccountLookupResult{Index: uint32(idx), ID: lookup.ID}
+		account, ok := found[lookup.ID]
+		if !ok {
 			continue
 		}
+		results[idx].Found = true
+		results[idx].Account, results[idx].Err = toAccount(account, lookup)
+	}
+	return results, nil
+}
 
-		// To TBDB's account.
-		flags := account.AccountFlags()
-		accounts = append(accounts, Account{
-			ID:             fromBinding(account.ID),
-			DebitsPending:  monetary.SetUint128Value(fromBinding(account.DebitsPending)),
-			DebitsPosted:   monetary.SetUint128Value(fromBinding(account.DebitsPosted)),
-			CreditsPending: monetary.SetUint128Value(fromBinding(account.CreditsPending)),
-			CreditsPosted:  monetary.SetUint128Value(fromBinding(account.CreditsPosted)),
-			UserData128:    fromBinding(account.UserData128),
-			UserData64:     account.UserData64,
-			UserData32:     account.UserData32,
-			Reserved:       account.Reserved,
-			Ledger:         account.Ledger,
-			Code:           account.Code,
-			Flags: AccountFlags{
-				Linked:                     flags.Linked,
-				DebitsMustNotExceedCredits: flags.DebitsMustNotExceedCredits,
-				CreditsMustNotExceedDebits: flags.CreditsMustNotExceedDebits,
-				History:                    flags.History,
-				Imported:                   flags.Imported,
-				Closed:                     flags.Closed,
-			},
-			Timestamp: account.Timestamp,
-		})
+// LookupAccounts fetchs one or more accounts by their ids alongside the monetary.
+// Returns the existing accounts in the request order, use LookupAccountsOrdered to report the missing ones.
+// Returns error if any monetary or ledger mismatches the account.
+func (i *Instance) LookupAccounts(lookups []AccountLookup) ([]Account, error) {
+	results, err := i.LookupAccountsOrdered(lookups)
+	if err != nil {
+		return nil, err
+	}
+	return foundAccounts(results)
+}
+
+// LookupAccountsMap fetchs one or more accounts by their ids alongside the monetary, keyed by id.
+// Accounts that do not exist are absent. Returns ErrDuplicateAccountLookup if an id is looked up
+// with different monetaries, and error if any monetary or ledger mismatches the account.
+func (i *Instance) LookupAccountsMap(lookups []AccountLookup) (map[Uint128]Account, error) {
+	results, err := i.LookupAccountsOrdered(lookups)
+	if err != nil {
+		return nil, err
+	}
+	return accountsByID(results)
+}
+
+// foundAccounts returns the found accounts of the lookup results, or the first result error.
+func foundAccounts(results []AccountLookupResult) ([]Account, error) {
+	accounts := make([]Account, 0, len(results))
+	for _, result := range results {
+		if result.Err != nil {
+			return nil, fmt.Errorf("account %s: %w", result.ID.String(), result.Err)
+		}
+		if result.Found {
+			accounts = append(accounts, result.Account)
+		}
 	}
 	return accounts, nil
 }
+
+// accountsByID returns the found accounts of the lookup results keyed by id, or the first result error.
+func accountsByID(results []AccountLookupResult) (map[Uint128]Account, error) {
+	accounts := make(map[Uint128]Account, len(results))
+	for _, result := range results {
+		if result.Err != nil {
+			return nil, fmt.Errorf("account %s: %w", result.ID.String(), result.Err)
+		}
+		if !result.Found {
+			continue
+		}
+		if existing, ok := accounts[result.ID]; ok && !sameAccountAmounts(existing, result.Account) {
+			return nil, fmt.Errorf("%w: %s", ErrDuplicateAccountLookup, result.ID.String())
+		}
+		accounts[result.ID] = result.Account
+	}
+	return accounts, nil
+}
+
+// sameAccountAmounts reports whether both accounts have amounts of the same currency.
+// Custom Amount implementations can not be compared and are treated as the same.
+func sameAccountAmounts(a, b Account) bool {
+	ca, okA := a.CreditsPosted.(*amountCurrency)
+	cb, okB := b.CreditsPosted.(*amountCurrency)
+	return !okA || !okB || sameCurrency(ca.curr, cb.curr)
+}
+
+// toAccount converts TigerBeetle's Account to Account with the lookup monetary,
+// checking the monetary & ledger against the account ledger.
+func toAccount(account types.Account, lookup AccountLookup) (Account, error) {
+	if lookup.Ledger != nil {
+		if expected := uint32(lookup.Ledger.EncodeLedger()); expected != account.Ledger {
+			return Account{}, fmt.Errorf("%w: ledger %d, expected %d", ErrLedgerMismatch, account.Ledger, expected)
+		}
+	}
+
+	// Get monetary.
+	monetary := lookup.Monetary
+	if monetary == nil {
+		var err error
+		if monetary, err = monetaryOf(account.Ledger); err != nil {
+			return Account{}, err
+		}
+	} else if amount, ok := monetary.(*amountCurrency); ok {
+		// Amounts of unknown ledgers, e.g. custom Ledger, can not be checked.
+		if cur, err := ParseLedgerCode(account.Ledger); err == nil && !sameCurrency(cur, amount.curr) {
+			return Account{}, fmt.Errorf("%w: %s monetary on %s ledger", ErrCurrencyAmountMismatch, amount.curr.code, cur.code)
+		}
+	}
+
+	// To TBDB's account.
+	flags := account.AccountFlags()
+	return Account{
+		ID:             fromBinding(account.ID),
+		DebitsPending:  monetary.SetUint128Value(fromBinding(account.DebitsPending)),
+		DebitsPosted:   monetary.SetUint128Value(fromBinding(account.DebitsPosted)),
+		CreditsPending: monetary.SetUint128Value(fromBinding(account.CreditsPending)),
+		CreditsPosted:  monetary.SetUint128Value(fromBinding(account.CreditsPosted)),
+		UserData128:    fromBinding(account.UserData128),
+		UserData64:     account.UserData64,
+		UserData32:     account.UserData32,
+		Reserved:       account.Reserved,
+		Ledger:         account.Ledger,
+		Code:           account.Code,
+		Flags: AccountFlags{
+			Linked:                     flags.Linked,
+			DebitsMustNotExceedCredits: flags.DebitsMustNotExceedCredits,
+			CreditsMustNotExceedDebits: flags.CreditsMustNotExceedDebits,
+			History:                    flags.History,
+			Imported:                   flags.Imported,
+			Closed:                     flags.Closed,
+		},
+		Timestamp: account.Timestamp,
+	}, nil
+}
@@ -5,6 +5,7 @@ import (
 
 	"github.com/qoinlyid/qore"
 	"github.com/stretchr/testify/assert"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
 )
 
 func TestCreateCreateAccountsWithCategory(t *testing.T) {
@@ -93,3 +94,73 @@ func TestLookupAccounts(t *testing.T) {
 		)
 	}
 }
+
+func TestLookupAccountsOrdered(t *testing.T) {
+	client := &fakeClient{accounts: []types.Account{
+		{ID: types.ToUint128(1), Ledger: uint32(IDR.EncodeLedger()), CreditsPosted: types.ToUint128(150)},
+		{ID: types.ToUint128(2), Ledger: uint32(USD.EncodeLedger())},
+	}}
+	i := &Instance{client: client}
+
+	results, err := i.LookupAccountsOrdered([]AccountLookup{
+		{ID: Uint128FromUint64(2)},
+		{ID: Uint128FromUint64(3)},
+		{ID: Uint128FromUint64(1), Monetary: IDR.NewMonetary()},
+		{ID: Uint128FromUint64(1), Monetary: BTC.NewMonetary()},
+		{ID: Uint128FromUint64(2), Ledger: EUR},
+	})
+	assert.NoError(t, err)
+	assert.Len(t, results, 5, "LookupAccountsOrdered must return a result per lookup")
+
+	ids := make([]uint64, 0, len(results))
+	for idx, result := range results {
+		assert.Equal(t, uint32(idx), result.Index)
+		ids = append(ids, result.ID.Lo)
+	}
+	assert.Equal(t, []uint64{2, 3, 1, 1, 2}, ids, "LookupAccountsOrdered must keep the request order")
+	assert.True(t, results[0].Found)
+	assert.Equal(t, "USD 0.00", results[0].Account.CreditsPosted.Uint128ToString(), "monetary must be resolved from ledger")
+	assert.False(t, results[1].Found, "missing account must be reported as not found")
+	assert.NoError(t, results[1].Err)
+	assert.Equal(t, "IDR 1.50", results[2].Account.CreditsPosted.Uint128ToString())
+	assert.ErrorIs(t, results[3].Err, ErrCurrencyAmountMismatch)
+	assert.ErrorIs(t, results[4].Err, ErrLedgerMismatch)
+
+	accounts, err := i.LookupAccounts([]AccountLookup{{ID: Uint128FromUint64(2)}, {ID: Uint128FromUint64(3)}, {ID: Uint128FromUint64(1)}})
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 2)
+	assert.Equal(t, Uint128FromUint64(2), accounts[0].ID, "LookupAccounts must keep the request order")
+	assert.Equal(t, Uint128FromUint64(1), accounts[1].ID, "LookupAccounts must keep the request order")
+
+	_, err = i.LookupAccounts([]AccountLookup{{ID: Uint128FromUint64(1), Monetary: BTC.NewMonetary()}})
+	assert.ErrorIs(t, err, ErrCurrencyAmountMismatch)
+
+	byID, err := i.LookupAccountsMap([]AccountLookup{{ID: Uint128FromUint64(1)}, {ID: Uint128FromUint64(1)}, {ID: Uint128FromUint64(3)}})
+	assert.NoError(t, err)
+	assert.Len(t, byID, 1)
+	_, ok := byID[Uint128FromUint64(3)]
+	assert.False(t, ok, "missing account must be absent")
+}
+
+func TestAccountCategoryFlags(t *testing.T) {
+	tests := []struct {
+		category AccountCategory
+		want     AccountFlags
+		wantErr  error
+	}{
+		{AccountCategoryControl, AccountFlags{History: true}, nil},
+		{AccountCategoryBalance, AccountFlags{History: true, DebitsMustNotExceedCredits: true}, nil},
+		{AccountCategoryEquity, AccountFlags{History: true, DebitsMustNotExceedCredits: true}, nil},
+		{AccountCategoryExpense, AccountFlags{History: true, CreditsMustNotExceedDebits: true}, nil},
+		{AccountCategoryTesting, AccountFlags{}, nil},
+		{AccountCategory(1), AccountFlags{}, ErrUnknownCategory},
+	}
+
+	for _, tt := range tests {
+		got, err := tt.category.Flags()
+		assert.ErrorIs(t, err, tt.wantErr)
+		assert.Equal(t, tt.want, got, "Flags(%d) returned unexpected result", tt.category)
+	}
+	assert.Equal(t, NormalSideCredit, AccountCategoryEquity.NormalSide())
+	assert.Equal(t, NormalSideDebit, AccountCategoryExpense.NormalSide())
+}
@@ -0,0 +1,149 @@
+package tbdb
+
+import "slices"
+
+// AllocationPolicy defines how the leftover minor units of an allocation are distributed.
+type AllocationPolicy uint8
+
+// Enum of allocation policy.
+const (
+	// AllocationLargestRemainder gives the leftover units one by one to the parts with the largest remainder,
+	// ties go to the earlier part.
+	AllocationLargestRemainder AllocationPolicy = iota
+	// AllocationFirstParts gives the leftover units one by one starting from the first part.
+	AllocationFirstParts
+	// AllocationLastPart gives all leftover units to the last part, e.g. the platform.
+	AllocationLastPart
+)
+
+// Allocate splits the amount by ratios with AllocationLargestRemainder policy.
+// The parts always add up exactly to the amount.
+//
+// Example: For 100, Allocate(1, 1, 1) returns [34, 33, 33].
+func (a *amountCurrency) Allocate(ratios ...uint64) ([]Uint128, error) {
+	return AllocateWithPolicy(a.uint128Val, AllocationLargestRemainder, ratios...)
+}
+
+// AllocateWithPolicy splits the amount by ratios without losing or inventing minor units,
+// the leftover units of the truncated parts are distributed according to policy.
+// Zero ratios receive zero, but at least one ratio must not be zero.
+func AllocateWithPolicy(amount Uint128, policy AllocationPolicy, ratios ...uint64) ([]Uint128, error) {
+	// Validate & sum the ratios.
+	if len(ratios) == 0 {
+		return nil, ErrAllocationNoRatio
+	}
+	var total Uint128
+	for _, ratio := range ratios {
+		var overflow bool
+		if total, overflow = total.Add(Uint128FromUint64(ratio)); overflow {
+			return nil, ErrUint128Overflow
+		}
+	}
+	if total.IsZero() {
+		return nil, ErrAllocationNoRatio
+	}
+
+	// Truncated parts, keep the remainders to distribute the leftover.
+	parts := make([]Uint128, len(ratios))
+	remainders := make([]Uint128, len(ratios))
+	var allocated Uint128
+	for idx, ratio := range ratios {
+		result, err := mulDiv(amount, Uint128FromUint64(ratio), total, RoundingTruncate)
+		if err != nil {
+			return nil, err
+		}
+		parts[idx], remainders[idx] = result.Value, result.Remainder
+		allocated, _ = allocated.Add(result.Value)
+	}
+	leftover, _ := amount.Sub(allocated)
+
+	// The leftover is always less than the number of parts.
+	order := make([]int, 0, len(ratios))
+	for idx, ratio := range ratios {
+		if ratio > 0 {
+			order = append(order, idx)
+		}
+	}
+	switch policy {
+	case AllocationLargestRemainder:
+		slices.SortStableFunc(order, func(a, b int) int {
+			return remainders[b].Compare(remainders[a])
+		})
+	case AllocationFirstParts:
+	case AllocationLastPart:
+		last := order[len(order)-1]
+		parts[last], _ = parts[last].Add(leftover)
+		return parts, nil
+	default:
+		return nil, ErrUnknownAllocationPolicy
+	}
+	for _, idx := range order[:leftover.Lo] {
+		parts[idx], _ = parts[idx].Add(Uint128FromUint64(1))
+	}
+	return parts, nil
+}
+
+// AllocationLeg defines the receiver of an allocation part.
+type AllocationLeg struct {
+	// CreditAccountID is the account that receives the part; required.
+	CreditAccountID Uint128
+	// Ratio is the weight of the part against the other legs.
+	Ratio uint64
+	// Code is the transfer code of the leg; zero will use the base transfer code.
+	Code uint16
+	// UserData32 is 32-bit user-defined data of the leg.
+	UserData32 uint32
+}
+
+// AllocateTransfers splits the base transfer amount across the legs and returns one transfer per non-zero part
+// as a linked chain, each debited from the base debit account. The base credit account is ignored.
+//
+// If the base transfer is already linked to the next transfer, the last leg keeps the chain open.
+func AllocateTransfers(base TransferData, policy AllocationPolicy, legs ...AllocationLeg) ([]TransferData, error) {
+	// Validate.
+	if base.Amount == nil {
+		return nil, ErrMonetaryMustNotBeNil
+	}
+	ratios := make([]uint64, 0, len(legs))
+	for _, leg := range legs {
+		if leg.CreditAccountID.IsZero() {
+			return nil, ErrAccountIDMustNotBeZero
+		}
+		ratios = append(ratios, leg.Ratio)
+	}
+
+	// Allocate.
+	amount := uint128Amount(base.Amount)
+	parts, err := AllocateWithPolicy(amount.ToUint128FromValue(), policy, ratios...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Legs.
+	transfers := make([]TransferData, 0, len(legs))
+	for idx, leg := range legs {
+		if parts[idx].IsZero() {
+			continue
+		}
+		code := leg.Code
+		if code == 0 {
+			code = base.code
+		}
+		transfers = append(transfers, TransferData{
+			DebitAccountID:  base.DebitAccountID,
+			CreditAccountID: leg.CreditAccountID,
+			Amount:          amount.SetUint128Value(parts[idx]),
+			UserData128:     base.UserData128,
+			UserData64:      base.UserData64,
+			UserData32:      leg.UserData32,
+			Ledger:          base.Ledger,
+			code:            code,
+		})
+	}
+
+	// Link the chain, the last transfer closes the chain unless the base transfer keeps it open.
+	for idx := range transfers {
+		transfers[idx].flags.Linked = idx < len(transfers)-1 || base.flags.Linked
+	}
+	return transfers, nil
+}
@@ -0,0 +1,79 @@
+package tbdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllocateWithPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  uint64
+		policy  AllocationPolicy
+		ratios  []uint64
+		want    []uint64
+		wantErr error
+	}{
+		{"even split", 100, AllocationLargestRemainder, []uint64{1, 1, 1}, []uint64{34, 33, 33}, nil},
+		{"largest remainder", 1000, AllocationLargestRemainder, []uint64{15, 35, 50}, []uint64{150, 350, 500}, nil},
+		{"largest remainder uneven", 10, AllocationLargestRemainder, []uint64{3, 3, 4}, []uint64{3, 3, 4}, nil},
+		{"largest remainder picks largest", 5, AllocationLargestRemainder, []uint64{1, 2, 4}, []uint64{1, 1, 3}, nil},
+		{"first parts", 5, AllocationFirstParts, []uint64{1, 2, 4}, []uint64{1, 2, 2}, nil},
+		{"last part", 100, AllocationLastPart, []uint64{1, 1, 1}, []uint64{33, 33, 34}, nil},
+		{"zero ratio receives zero", 101, AllocationLargestRemainder, []uint64{1, 0, 1}, []uint64{51, 0, 50}, nil},
+		{"last part skips zero ratio", 101, AllocationLastPart, []uint64{1, 1, 0}, []uint64{50, 51, 0}, nil},
+		{"zero amount", 0, AllocationLargestRemainder, []uint64{1, 2}, []uint64{0, 0}, nil},
+		{"no ratio", 100, AllocationLargestRemainder, nil, nil, ErrAllocationNoRatio},
+		{"zero ratios", 100, AllocationLargestRemainder, []uint64{0, 0}, nil, ErrAllocationNoRatio},
+		{"unknown policy", 100, AllocationPolicy(9), []uint64{1}, nil, ErrUnknownAllocationPolicy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AllocateWithPolicy(Uint128FromUint64(tt.amount), tt.policy, tt.ratios...)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			want := make([]Uint128, 0, len(tt.want))
+			for _, part := range tt.want {
+				want = append(want, Uint128FromUint64(part))
+			}
+			assert.Equal(t, want, got, "AllocateWithPolicy returned unexpected parts")
+		})
+	}
+
+	// Parts of the full 128-bit range still add up exactly.
+	maxUint128 := Uint128{Hi: ^uint64(0), Lo: ^uint64(0)}
+	parts, err := AllocateWithPolicy(maxUint128, AllocationLargestRemainder, 7, 11, 13)
+	assert.NoError(t, err)
+	var sum Uint128
+	for _, part := range parts {
+		sum, _ = sum.Add(part)
+	}
+	assert.Equal(t, maxUint128, sum)
+}
+
+func TestAllocateTransfers(t *testing.T) {
+	seller, platform, partner := Uint128FromUint64(10), Uint128FromUint64(11), Uint128FromUint64(12)
+	transfers, err := AllocateTransfers(TransferData{
+		DebitAccountID: Uint128FromUint64(1),
+		Amount:         IDR.NewAmountFromFloat64(100.01),
+		Ledger:         IDR,
+	}, AllocationLargestRemainder,
+		AllocationLeg{CreditAccountID: seller, Ratio: 90},
+		AllocationLeg{CreditAccountID: platform, Ratio: 7},
+		AllocationLeg{CreditAccountID: partner, Ratio: 3},
+	)
+	assert.NoError(t, err)
+	assert.Len(t, transfers, 3)
+
+	want := []uint64{9_001, 700, 300}
+	for idx, transfer := range transfers {
+		assert.Equal(t, Uint128FromUint64(want[idx]), transfer.Amount.ToUint128FromValue())
+		assert.Equal(t, Uint128FromUint64(1), transfer.DebitAccountID)
+		assert.Equal(t, idx < len(transfers)-1, transfer.flags.Linked, "linked flag of transfer %d", idx)
+	}
+}
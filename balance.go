@@ -0,0 +1,202 @@
+package tbdb
+
+import (
+	"fmt"
+	"strings"
+)
+
+// NormalSide defines the side that increases an account balance.
+type NormalSide uint8
+
+// Enum of normal side.
+const (
+	// Normal side of accounts whose balance is credits minus debits, e.g. wallets, liabilities and income.
+	NormalSideCredit NormalSide = iota + 1
+	// Normal side of accounts whose balance is debits minus credits, e.g. assets and control accounts.
+	NormalSideDebit
+)
+
+// String implements fmt.Stringer.
+func (s NormalSide) String() string {
+	switch s {
+	case NormalSideCredit:
+		return "credit"
+	case NormalSideDebit:
+		return "debit"
+	}
+	return "unknown"
+}
+
+// MarshalText implements encoding.TextMarshaler, e.g. for JSON or YAML chart of accounts.
+func (s NormalSide) MarshalText() ([]byte, error) {
+	if s != NormalSideCredit && s != NormalSideDebit {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownNormalSide, s)
+	}
+	return []byte(s.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, accepts "credit" or "debit".
+func (s *NormalSide) UnmarshalText(text []byte) error {
+	switch strings.ToLower(strings.TrimSpace(string(text))) {
+	case "credit":
+		*s = NormalSideCredit
+	case "debit":
+		*s = NormalSideDebit
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownNormalSide, text)
+	}
+	return nil
+}
+
+// NormalSide returns the normal side of the category, zero for unknown category.
+//
+//   - AccountCategoryBalance, AccountCategoryIncome, AccountCategoryLiabilities, AccountCategoryEquity: NormalSideCredit
+//   - AccountCategoryControl, AccountCategoryExpense: NormalSideDebit
+func (c AccountCategory) NormalSide() NormalSide {
+	switch c {
+	case AccountCategoryBalance, AccountCategoryIncome, AccountCategoryLiabilities, AccountCategoryEquity:
+		return NormalSideCredit
+	case AccountCategoryControl, AccountCategoryExpense:
+		return NormalSideDebit
+	}
+	return 0
+}
+
+// NormalSide returns the normal side of the account, derived from the AccountCategory of its code,
+// otherwise from its flags: CreditsMustNotExceedDebits is debit normal, anything else is credit normal.
+func (a Account) NormalSide() NormalSide {
+	if side := AccountCategory(a.Code).NormalSide(); side != 0 {
+		return side
+	}
+	if a.Flags.CreditsMustNotExceedDebits {
+		return NormalSideDebit
+	}
+	return NormalSideCredit
+}
+
+// SignedAmount is a signed balance, since TigerBeetle amounts are unsigned.
+type SignedAmount struct {
+	// Magnitude is the absolute value in the account monetary.
+	Magnitude Amount
+	// Negative reports whether the value is below zero, never true for zero magnitude.
+	Negative bool
+}
+
+// Sign returns -1 if s < 0, 0 if s == 0, 1 if s > 0.
+func (s SignedAmount) Sign() int {
+	switch {
+	case s.Magnitude == nil || s.Magnitude.IsZero():
+		return 0
+	case s.Negative:
+		return -1
+	}
+	return 1
+}
+
+// String formats the balance, e.g. "IDR -20,000.50".
+func (s SignedAmount) String() string {
+	if s.Magnitude == nil {
+		return "0"
+	}
+	if a, ok := s.Magnitude.(*amountCurrency); ok {
+		return a.Format(FormatOptions{Negative: s.Negative})
+	}
+	if s.Negative && !s.Magnitude.IsZero() {
+		return "-" + s.Magnitude.Uint128ToString()
+	}
+	return s.Magnitude.Uint128ToString()
+}
+
+// accountSides defines the debits & credits of an account or balance record.
+type accountSides struct {
+	monetary                                                   Amount
+	debitsPending, debitsPosted, creditsPending, creditsPosted Uint128
+}
+
+// newAccountSides returns the debits & credits values, nil amounts are zero.
+func newAccountSides(debitsPending, debitsPosted, creditsPending, creditsPosted Amount) accountSides {
+	value := func(a Amount) Uint128 {
+		if a == nil {
+			return Uint128{}
+		}
+		return a.ToUint128FromValue()
+	}
+	return accountSides{
+		monetary:       creditsPosted,
+		debitsPending:  value(debitsPending),
+		debitsPosted:   value(debitsPosted),
+		creditsPending: value(creditsPending),
+		creditsPosted:  value(creditsPosted),
+	}
+}
+
+// signed returns increase - decrease, swapped for debit normal side, in the monetary.
+// TigerBeetle rejects transfers that overflow debits or credits, so the sums do not overflow.
+func (s accountSides) signed(side NormalSide, credits, debits Uint128) SignedAmount {
+	if side == NormalSideDebit {
+		credits, debits = debits, credits
+	}
+	var result SignedAmount
+	diff, negative := credits.Sub(debits)
+	if negative {
+		diff, _ = debits.Sub(credits)
+		result.Negative = true
+	}
+	if s.monetary != nil {
+		result.Magnitude = s.monetary.SetUint128Value(diff)
+	}
+	return result
+}
+
+// posted returns the posted balance.
+func (s accountSides) posted(side NormalSide) SignedAmount {
+	return s.signed(side, s.creditsPosted, s.debitsPosted)
+}
+
+// pending returns the balance including pending debits & credits.
+func (s accountSides) pending(side NormalSide) SignedAmount {
+	credits, _ := s.creditsPosted.Add(s.creditsPending)
+	debits, _ := s.debitsPosted.Add(s.debitsPending)
+	return s.signed(side, credits, debits)
+}
+
+// available returns the posted balance minus pending decreases, pending increases are not available yet.
+func (s accountSides) available(side NormalSide) SignedAmount {
+	if side == NormalSideDebit {
+		credits, _ := s.creditsPosted.Add(s.creditsPending)
+		return s.signed(side, credits, s.debitsPosted)
+	}
+	debits, _ := s.debitsPosted.Add(s.debitsPending)
+	return s.signed(side, s.creditsPosted, debits)
+}
+
+// sides returns the debits & credits of the account.
+func (a Account) sides() accountSides {
+	return newAccountSides(a.DebitsPending, a.DebitsPosted, a.CreditsPending, a.CreditsPosted)
+}
+
+// PostedBalance returns the posted balance on the account normal side.
+func (a Account) PostedBalance() SignedAmount { return a.sides().posted(a.NormalSide()) }
+
+// PendingBalance returns the balance including pending transfers on the account normal side.
+func (a Account) PendingBalance() SignedAmount { return a.sides().pending(a.NormalSide()) }
+
+// AvailableBalance returns the posted balance minus pending decreases on the account normal side,
+// e.g. credits posted - debits posted - debits pending for a wallet.
+func (a Account) AvailableBalance() SignedAmount { return a.sides().available(a.NormalSide()) }
+
+// sides returns the debits & credits of the balance record.
+func (b AccountBalance) sides() accountSides {
+	return newAccountSides(b.DebitsPending, b.DebitsPosted, b.CreditsPending, b.CreditsPosted)
+}
+
+// PostedBalance returns the posted balance on the given normal side, see Account.NormalSide.
+func (b AccountBalance) PostedBalance(side NormalSide) SignedAmount { return b.sides().posted(side) }
+
+// PendingBalance returns the balance including pending transfers on the given normal side.
+func (b AccountBalance) PendingBalance(side NormalSide) SignedAmount { return b.sides().pending(side) }
+
+// AvailableBalance returns the posted balance minus pending decreases on the given normal side.
+func (b AccountBalance) AvailableBalance(side NormalSide) SignedAmount {
+	return b.sides().available(side)
+}
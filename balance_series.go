@@ -0,0 +1,189 @@
+package tbdb
+
+import (
+	"fmt"
+	"time"
+)
+
+// BalanceInterval defines the calendar period of a balance series.
+type BalanceInterval uint8
+
+// Enum of balance interval.
+const (
+	// Calendar days, from midnight to midnight.
+	BalanceIntervalDay BalanceInterval = iota + 1
+	// Calendar weeks, from Monday midnight, see ISO 8601.
+	BalanceIntervalWeek
+	// Calendar months, from the first day midnight.
+	BalanceIntervalMonth
+)
+
+// String implements fmt.Stringer.
+func (i BalanceInterval) String() string {
+	switch i {
+	case BalanceIntervalDay:
+		return "day"
+	case BalanceIntervalWeek:
+		return "week"
+	case BalanceIntervalMonth:
+		return "month"
+	}
+	return "unknown"
+}
+
+// start returns the start of the period containing t, in the location of t.
+func (i BalanceInterval) start(t time.Time) time.Time {
+	y, m, d := t.Date()
+	switch i {
+	case BalanceIntervalWeek:
+		d -= (int(t.Weekday()) + 6) % 7
+	case BalanceIntervalMonth:
+		d = 1
+	}
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// next returns the start of the period following the one starting at t.
+func (i BalanceInterval) next(t time.Time) time.Time {
+	y, m, d := t.Date()
+	switch i {
+	case BalanceIntervalWeek:
+		d += 7
+	case BalanceIntervalMonth:
+		m++
+	default:
+		d++
+	}
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// BalanceSeriesFilter defines the account & calendar range of a balance series.
+type BalanceSeriesFilter struct {
+	// AccountID is unique identifier of account. Required or must not be zero.
+	AccountID Uint128
+	// From is the first date of the series, inclusive, its time of day is ignored. Required.
+	From time.Time
+	// To is the last date of the series, inclusive, its time of day is ignored. Required.
+	To time.Time
+	// Location of the calendar, e.g. time.LoadLocation("Asia/Jakarta").
+	// Optional; if nil, time.UTC is used.
+	Location *time.Location
+	// Interval of the periods.
+	// Optional; if zero, BalanceIntervalDay is used.
+	Interval BalanceInterval
+	// Monetary represent account monetary type.
+	// Optional; if nil, it is resolved from the account ledger.
+	Monetary Amount
+}
+
+// BalancePeriod defines the balances of an account in a calendar period.
+type BalancePeriod struct {
+	// Start of the period, inclusive, in the series location.
+	Start time.Time
+	// End of the period, exclusive, in the series location.
+	End time.Time
+	// Opening is the posted balance on the account normal side at Start.
+	Opening SignedAmount
+	// Closing is the posted balance on the account normal side at End.
+	Closing SignedAmount
+	// TotalDebits is the sum of the posted debits of the period.
+	TotalDebits Amount
+	// TotalCredits is the sum of the posted credits of the period.
+	TotalCredits Amount
+	// Count is the number of balance changes of the period:
+	// transfers, including pending ones, posts & voids, and expiries of pending transfers.
+	Count int
+}
+
+// GetBalanceSeries returns the posted balances of the account for each calendar period from filter.From
+// to filter.To in the filter location, built from the historical balances, so the account must have the
+// History flag, otherwise returns ErrAccountWithoutHistory. Periods without activity carry the previous closing
+// balance forward, the opening balance of the first period is the latest balance before it.
+//
+// Closing balances reconcile with the totals, e.g. Opening + TotalCredits - TotalDebits for a credit normal account.
+func (i *Instance) GetBalanceSeries(filter BalanceSeriesFilter) ([]BalancePeriod, error) {
+	// Validate.
+	switch {
+	case filter.From.IsZero():
+		return nil, ErrTimeMinMustNotBeZero
+	case filter.To.IsZero():
+		return nil, ErrTimeMaxMustNotBeZero
+	case filter.Interval > BalanceIntervalMonth:
+		return nil, fmt.Errorf("%w: %d", ErrUnknownBalanceInterval, filter.Interval)
+	}
+	if filter.Interval == 0 {
+		filter.Interval = BalanceIntervalDay
+	}
+	if filter.Location == nil {
+		filter.Location = time.UTC
+	}
+	start := filter.Interval.start(filter.From.In(filter.Location))
+	end := filter.Interval.next(filter.Interval.start(filter.To.In(filter.Location)))
+	if !start.Before(end) {
+		return nil, fmt.Errorf("%w: %s after %s", ErrInvalidBalanceSeriesRange, filter.From, filter.To)
+	}
+
+	// Account & balance before the series.
+	balanceFilter := AccountTransferFilter{
+		TimeMin:   start,
+		TimeMax:   end.Add(-time.Nanosecond),
+		AccountID: filter.AccountID,
+		Limit:     uint32(TigerBeetleMaxBatch),
+		Monetary:  filter.Monetary,
+		Flags:     AccountFilterFlags{Debits: true, Credits: true},
+	}
+	account, _, err := i.statementAccount(&balanceFilter)
+	if err != nil {
+		return nil, err
+	}
+	running, err := i.balanceBefore(balanceFilter, start)
+	if err != nil {
+		return nil, err
+	}
+
+	// Periods.
+	var periods []BalancePeriod
+	for at := start; at.Before(end); at = filter.Interval.next(at) {
+		periods = append(periods, BalancePeriod{Start: at, End: filter.Interval.next(at)})
+	}
+	var (
+		side    = account.NormalSide()
+		opening = running
+		current int
+	)
+	closePeriod := func() {
+		period := &periods[current]
+		debits, _ := running.debitsPosted.Sub(opening.debitsPosted)
+		credits, _ := running.creditsPosted.Sub(opening.creditsPosted)
+		period.Opening = opening.posted(side)
+		period.Closing = running.posted(side)
+		period.TotalDebits = balanceFilter.Monetary.SetUint128Value(debits)
+		period.TotalCredits = balanceFilter.Monetary.SetUint128Value(credits)
+		opening = running
+		current++
+	}
+
+	// Page the balances of the series in chronological order.
+	for {
+		balances, err := i.GetHisotricalBalances(balanceFilter)
+		if err != nil {
+			return nil, err
+		}
+		for _, balance := range balances {
+			at := time.Unix(0, int64(balance.Timestamp))
+			for !at.Before(periods[current].End) {
+				closePeriod()
+			}
+			running = balance.sides()
+			periods[current].Count++
+		}
+		if len(balances) < int(balanceFilter.Limit) {
+			break
+		}
+		balanceFilter.TimeMin = time.Unix(0, int64(balances[len(balances)-1].Timestamp+1))
+	}
+	for current < len(periods) {
+		closePeriod()
+	}
+	return periods, nil
+}
@@ -0,0 +1,212 @@
+package tbdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+// testBalanceSeriesFixture is a wallet with 10.00 IDR on Jul 20, then in loc time credits 1.00 on Aug 1 23:30,
+// debits 0.50 on Aug 2 00:30, holds 0.20 on Aug 2 09:00 and credits 2.00 on Aug 4 12:00.
+func testBalanceSeriesFixture(loc *time.Location) fakeClient {
+	value := func(v uint64) types.Uint128 { return toBinding(Uint128FromUint64(v)) }
+	c := fakeClient{
+		accounts: []types.Account{{
+			ID:     toBinding(Uint128FromUint64(1)),
+			Ledger: uint32(IDR.EncodeLedger()),
+			Code:   uint16(AccountCategoryBalance),
+			Flags:  types.AccountFlags{History: true}.ToUint16(),
+		}},
+	}
+	balance := func(at time.Time, debitsPending, debitsPosted, creditsPosted uint64) {
+		c.balances = append(c.balances, types.AccountBalance{
+			Timestamp:     uint64(at.UnixNano()),
+			DebitsPending: value(debitsPending),
+			DebitsPosted:  value(debitsPosted),
+			CreditsPosted: value(creditsPosted),
+		})
+	}
+	balance(time.Date(2025, time.July, 20, 10, 0, 0, 0, loc), 0, 0, 1000)
+	balance(time.Date(2025, time.August, 1, 23, 30, 0, 0, loc), 0, 0, 1100)
+	balance(time.Date(2025, time.August, 2, 0, 30, 0, 0, loc), 0, 50, 1100)
+	balance(time.Date(2025, time.August, 2, 9, 0, 0, 0, loc), 20, 50, 1100)
+	balance(time.Date(2025, time.August, 4, 12, 0, 0, 0, loc), 20, 50, 1300)
+	return c
+}
+
+func TestGetBalanceSeries(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+
+	type period struct {
+		start           time.Time
+		opening, closed string
+		debits, credits uint64
+		count           int
+	}
+	date := func(l *time.Location, month time.Month, day int) time.Time {
+		return time.Date(2025, month, day, 0, 0, 0, 0, l)
+	}
+	tests := []struct {
+		name     string
+		location *time.Location
+		interval BalanceInterval
+		want     []period
+	}{
+		{
+			name:     "daily",
+			location: loc,
+			want: []period{
+				{date(loc, time.August, 1), "IDR 10.00", "IDR 11.00", 0, 100, 1},
+				{date(loc, time.August, 2), "IDR 11.00", "IDR 10.50", 50, 0, 2},
+				{date(loc, time.August, 3), "IDR 10.50", "IDR 10.50", 0, 0, 0},
+				{date(loc, time.August, 4), "IDR 10.50", "IDR 12.50", 0, 200, 1},
+				{date(loc, time.August, 5), "IDR 12.50", "IDR 12.50", 0, 0, 0},
+			},
+		},
+		{
+			name:     "daily utc",
+			interval: BalanceIntervalDay,
+			want: []period{
+				{date(time.UTC, time.August, 1), "IDR 10.00", "IDR 10.50", 50, 100, 2},
+				{date(time.UTC, time.August, 2), "IDR 10.50", "IDR 10.50", 0, 0, 1},
+				{date(time.UTC, time.August, 3), "IDR 10.50", "IDR 10.50", 0, 0, 0},
+				{date(time.UTC, time.August, 4), "IDR 10.50", "IDR 12.50", 0, 200, 1},
+				{date(time.UTC, time.August, 5), "IDR 12.50", "IDR 12.50", 0, 0, 0},
+			},
+		},
+		{
+			name:     "weekly",
+			location: loc,
+			interval: BalanceIntervalWeek,
+			want: []period{
+				{date(loc, time.July, 28), "IDR 10.00", "IDR 10.50", 50, 100, 3},
+				{date(loc, time.August, 4), "IDR 10.50", "IDR 12.50", 0, 200, 1},
+			},
+		},
+		{
+			name:     "monthly",
+			location: loc,
+			interval: BalanceIntervalMonth,
+			want: []period{
+				{date(loc, time.August, 1), "IDR 10.00", "IDR 12.50", 50, 300, 4},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := testBalanceSeriesFixture(loc)
+			i := &Instance{client: &fake}
+			l := tt.location
+			if l == nil {
+				l = time.UTC
+			}
+			periods, err := i.GetBalanceSeries(BalanceSeriesFilter{
+				AccountID: Uint128FromUint64(1),
+				From:      time.Date(2025, time.August, 1, 15, 0, 0, 0, l),
+				To:        time.Date(2025, time.August, 5, 8, 0, 0, 0, l),
+				Location:  tt.location,
+				Interval:  tt.interval,
+			})
+			require.NoError(t, err)
+			require.Len(t, periods, len(tt.want))
+			for idx, want := range tt.want {
+				got := periods[idx]
+				assert.True(t, want.start.Equal(got.Start), "start %d: %s", idx, got.Start)
+				assert.Equal(t, want.opening, got.Opening.String(), "opening %d", idx)
+				assert.Equal(t, want.closed, got.Closing.String(), "closing %d", idx)
+				assert.Equal(t, Uint128FromUint64(want.debits), got.TotalDebits.ToUint128FromValue(), "debits %d", idx)
+				assert.Equal(t, Uint128FromUint64(want.credits), got.TotalCredits.ToUint128FromValue(), "credits %d", idx)
+				assert.Equal(t, want.count, got.Count, "count %d", idx)
+				if idx > 0 {
+					assert.True(t, periods[idx-1].End.Equal(got.Start), "contiguous %d", idx)
+				}
+			}
+		})
+	}
+}
+
+func TestGetBalanceSeriesInvalid(t *testing.T) {
+	from := time.Date(2025, time.August, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		filter BalanceSeriesFilter
+		err    error
+	}{
+		{"zero from", BalanceSeriesFilter{AccountID: Uint128FromUint64(1), To: from}, ErrTimeMinMustNotBeZero},
+		{"zero to", BalanceSeriesFilter{AccountID: Uint128FromUint64(1), From: from}, ErrTimeMaxMustNotBeZero},
+		{
+			"unknown interval",
+			BalanceSeriesFilter{AccountID: Uint128FromUint64(1), From: from, To: from, Interval: 9},
+			ErrUnknownBalanceInterval,
+		},
+		{
+			"from after to",
+			BalanceSeriesFilter{AccountID: Uint128FromUint64(1), From: from, To: from.AddDate(0, 0, -1)},
+			ErrInvalidBalanceSeriesRange,
+		},
+		{"zero account", BalanceSeriesFilter{From: from, To: from}, ErrAccountIDMustNotBeZero},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := testBalanceSeriesFixture(time.UTC)
+			i := &Instance{client: &fake}
+			_, err := i.GetBalanceSeries(tt.filter)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	// Without history, every period would be flat at zero.
+	fake := modified(testBalanceSeriesFixture(time.UTC), func(c *fakeClient) { c.accounts[0].Flags = 0 })
+	_, err := (&Instance{client: &fake}).GetBalanceSeries(BalanceSeriesFilter{AccountID: Uint128FromUint64(1), From: from, To: from})
+	assert.ErrorIs(t, err, ErrAccountWithoutHistory)
+}
+
+func TestBalanceIntervalStart(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+	// Sunday evening.
+	at := time.Date(2025, time.August, 10, 22, 15, 0, 0, loc)
+
+	assert.Equal(t, time.Date(2025, time.August, 10, 0, 0, 0, 0, loc), BalanceIntervalDay.start(at))
+	assert.Equal(t, time.Date(2025, time.August, 4, 0, 0, 0, 0, loc), BalanceIntervalWeek.start(at))
+	assert.Equal(t, time.Date(2025, time.August, 1, 0, 0, 0, 0, loc), BalanceIntervalMonth.start(at))
+	assert.Equal(t, time.Date(2025, time.August, 11, 0, 0, 0, 0, loc), BalanceIntervalDay.next(at))
+	assert.Equal(t, time.Date(2026, time.January, 1, 0, 0, 0, 0, loc),
+		BalanceIntervalMonth.next(time.Date(2025, time.December, 1, 0, 0, 0, 0, loc)))
+	assert.Equal(t, "week", BalanceIntervalWeek.String())
+}
+
+func TestGetBalanceSeriesPages(t *testing.T) {
+	at := time.Date(2025, time.August, 6, 0, 0, 0, 0, time.UTC)
+	rows := int(TigerBeetleMaxBatch) + 10
+	fake := modified(testBalanceSeriesFixture(time.UTC), func(c *fakeClient) {
+		last := c.balances[len(c.balances)-1]
+		for idx := 1; idx <= rows; idx++ {
+			credits, _ := fromBinding(last.CreditsPosted).Add(Uint128FromUint64(uint64(idx)))
+			c.balances = append(c.balances, types.AccountBalance{
+				Timestamp:     uint64(at.Add(time.Duration(idx) * time.Second).UnixNano()),
+				DebitsPending: last.DebitsPending,
+				DebitsPosted:  last.DebitsPosted,
+				CreditsPosted: toBinding(credits),
+			})
+		}
+	})
+	i := &Instance{client: &fake}
+
+	periods, err := i.GetBalanceSeries(BalanceSeriesFilter{
+		AccountID: Uint128FromUint64(1),
+		From:      at,
+		To:        at.AddDate(0, 0, 1),
+	})
+	require.NoError(t, err)
+	require.Len(t, periods, 2)
+	assert.Equal(t, "IDR 12.50", periods[0].Opening.String())
+	assert.Equal(t, rows, periods[0].Count)
+	assert.Equal(t, Uint128FromUint64(uint64(rows)), periods[0].TotalCredits.ToUint128FromValue())
+	assert.Equal(t, periods[0].Closing, periods[1].Opening)
+	assert.Equal(t, 0, periods[1].Count)
+}
@@ -0,0 +1,64 @@
+package tbdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAccountNormalSide(t *testing.T) {
+	tests := []struct {
+		name    string
+		account Account
+		want    NormalSide
+	}{
+		{"balance", Account{Code: uint16(AccountCategoryBalance)}, NormalSideCredit},
+		{"liabilities", Account{Code: uint16(AccountCategoryLiabilities)}, NormalSideCredit},
+		{"control", Account{Code: uint16(AccountCategoryControl)}, NormalSideDebit},
+		{"custom credit flag", Account{Code: 1, Flags: AccountFlags{DebitsMustNotExceedCredits: true}}, NormalSideCredit},
+		{"custom debit flag", Account{Code: 1, Flags: AccountFlags{CreditsMustNotExceedDebits: true}}, NormalSideDebit},
+		{"custom no flag", Account{Code: 1}, NormalSideCredit},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.account.NormalSide(), "NormalSide returned unexpected result")
+		})
+	}
+}
+
+func TestAccountBalances(t *testing.T) {
+	amount := func(v uint64) Amount { return IDR.NewAmountFromMinorUnits(Uint128FromUint64(v)) }
+	wallet := Account{
+		Code:           uint16(AccountCategoryBalance),
+		DebitsPending:  amount(100),
+		DebitsPosted:   amount(300),
+		CreditsPending: amount(50),
+		CreditsPosted:  amount(1000),
+	}
+	control := wallet
+	control.Code = uint16(AccountCategoryControl)
+
+	tests := []struct {
+		name string
+		got  SignedAmount
+		want string
+		sign int
+	}{
+		{"wallet posted", wallet.PostedBalance(), "IDR 7.00", 1},
+		{"wallet pending", wallet.PendingBalance(), "IDR 6.50", 1},
+		{"wallet available", wallet.AvailableBalance(), "IDR 6.00", 1},
+		{"control posted", control.PostedBalance(), "IDR -7.00", -1},
+		{"control pending", control.PendingBalance(), "IDR -6.50", -1},
+		{"control available", control.AvailableBalance(), "IDR -7.50", -1},
+		{"zero", Account{CreditsPosted: IDR.NewMonetary()}.PostedBalance(), "IDR 0.00", 0},
+		{"balance record", AccountBalance{DebitsPosted: amount(300), CreditsPosted: amount(1000)}.PostedBalance(NormalSideDebit), "IDR -7.00", -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.got.String(), "balance returned unexpected result")
+			assert.Equal(t, tt.sign, tt.got.Sign(), "Sign returned unexpected result")
+		})
+	}
+}
@@ -0,0 +1,373 @@
+package tbdb
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// ChartOfAccounts defines the account types and the system accounts of the ledgers, e.g. fee revenue,
+// settlement or float accounts. System accounts have deterministic IDs, so provisioning is idempotent.
+//
+//	namespace: 6ba7b810-9dad-11d1-80b4-00c04fd430c8
+//	types:
+//	  - name: assets
+//	    normal_side: debit
+//	    code_min: 1100
+//	    code_max: 1999
+//	  - name: assets.settlement
+//	    parent: assets
+//	    code_min: 1100
+//	    code_max: 1199
+//	accounts:
+//	  - key: settlement.bank
+//	    type: assets.settlement
+//	    ledgers: [IDR, USD]
+type ChartOfAccounts struct {
+	// Namespace scopes the deterministic IDs of the system accounts, as UUID or hex in YAML & JSON; required.
+	Namespace Uint128 `json:"namespace" mapstructure:"namespace"`
+	// Types is the account type hierarchy.
+	Types []AccountType `json:"types" mapstructure:"types"`
+	// Accounts is the system accounts to provision.
+	Accounts []SystemAccount `json:"accounts" mapstructure:"accounts"`
+}
+
+// AccountType defines an account type of the chart of accounts.
+// Child types inherit normal side, flags and code range of their parent, unless set.
+// Code ranges must not overlap the built-in AccountCategory codes, which have their own normal side.
+type AccountType struct {
+	// Name is the unique type name, e.g. "assets.settlement"; required.
+	Name string `json:"name" mapstructure:"name"`
+	// Parent is the parent type name; empty for root types.
+	Parent string `json:"parent" mapstructure:"parent"`
+	// NormalSide is the side that increases the balance; required for root types.
+	NormalSide NormalSide `json:"normal_side" mapstructure:"normal_side"`
+	// Flags is the account flags policy; nil inherits the parent policy, or for root types and types with
+	// another normal side than their parent, is History and the flag of the normal side:
+	// CreditsMustNotExceedDebits for debit, DebitsMustNotExceedCredits for credit.
+	// Debit normal types must set CreditsMustNotExceedDebits, since Account.NormalSide is derived from it.
+	Flags *AccountFlagPolicy `json:"flags" mapstructure:"flags"`
+	// CodeMin & CodeMax is the account code range, within the parent range; required for root types.
+	CodeMin uint16 `json:"code_min" mapstructure:"code_min"`
+	CodeMax uint16 `json:"code_max" mapstructure:"code_max"`
+}
+
+// AccountFlagPolicy defines the account flags of an account type.
+type AccountFlagPolicy struct {
+	// History keeps the historical balances, see GetHisotricalBalances.
+	History bool `json:"history" mapstructure:"history"`
+	// DebitsMustNotExceedCredits rejects transfers that make credit normal balance negative.
+	DebitsMustNotExceedCredits bool `json:"debits_must_not_exceed_credits" mapstructure:"debits_must_not_exceed_credits"`
+	// CreditsMustNotExceedDebits rejects transfers that make debit normal balance negative.
+	CreditsMustNotExceedDebits bool `json:"credits_must_not_exceed_debits" mapstructure:"credits_must_not_exceed_debits"`
+}
+
+// SystemAccount defines a system account to provision on each of its ledgers.
+type SystemAccount struct {
+	// Key is the unique account key that seeds the deterministic ID, e.g. "fees.revenue"; required.
+	Key string `json:"key" mapstructure:"key"`
+	// Type is the account type name; required.
+	Type string `json:"type" mapstructure:"type"`
+	// Code is the account code within the type code range; zero uses the type CodeMin.
+	Code uint16 `json:"code" mapstructure:"code"`
+	// Ledgers is the registered currency codes to provision the account on; required.
+	Ledgers []string `json:"ledgers" mapstructure:"ledgers"`
+}
+
+// defaultFlagPolicy returns the flags policy of the normal side, see AccountType.Flags.
+func defaultFlagPolicy(side NormalSide) AccountFlagPolicy {
+	return AccountFlagPolicy{
+		History:                    true,
+		DebitsMustNotExceedCredits: side == NormalSideCredit,
+		CreditsMustNotExceedDebits: side == NormalSideDebit,
+	}
+}
+
+// resolvedAccountType defines an account type with the inherited values.
+type resolvedAccountType struct {
+	AccountType
+	flags   AccountFlagPolicy
+	parents []string
+}
+
+// LoadChartOfAccounts loads the chart of accounts from YAML, JSON or TOML file.
+func LoadChartOfAccounts(path string) (*ChartOfAccounts, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType(strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), "."))
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read chart of accounts %s: %w", path, err)
+	}
+	var chart ChartOfAccounts
+	if err := v.Unmarshal(&chart, viper.DecodeHook(chartOfAccountsHook)); err != nil {
+		return nil, fmt.Errorf("failed to parse chart of accounts %s: %w", path, err)
+	}
+	if err := chart.Validate(); err != nil {
+		return nil, err
+	}
+	return &chart, nil
+}
+
+// chartOfAccountsHook decodes the namespace as UUID or hex and the normal side as text.
+func chartOfAccountsHook(from, to reflect.Type, data any) (any, error) {
+	if from.Kind() != reflect.String {
+		return data, nil
+	}
+	switch to {
+	case reflect.TypeOf(Uint128{}):
+		raw := strings.TrimSpace(data.(string))
+		if id, err := Uint128FromUUID(raw); err == nil {
+			return id, nil
+		}
+		return Uint128FromHex(raw)
+	case reflect.TypeOf(NormalSide(0)):
+		var side NormalSide
+		err := side.UnmarshalText([]byte(data.(string)))
+		return side, err
+	}
+	return data, nil
+}
+
+// Validate validates the type hierarchy, code ranges, flag policies and system accounts.
+func (c *ChartOfAccounts) Validate() error {
+	if c.Namespace.IsZero() {
+		return fmt.Errorf("%w: namespace must not be zero", ErrInvalidChartOfAccounts)
+	}
+	types, err := c.resolveTypes()
+	if err != nil {
+		return err
+	}
+
+	keys := make(map[string]struct{}, len(c.Accounts))
+	for _, account := range c.Accounts {
+		switch {
+		case account.Key == "":
+			return fmt.Errorf("%w: account key must not be empty", ErrInvalidChartOfAccounts)
+		case len(account.Ledgers) == 0:
+			return fmt.Errorf("%w: account %s has no ledgers", ErrInvalidChartOfAccounts, account.Key)
+		}
+		if _, ok := keys[account.Key]; ok {
+			return fmt.Errorf("%w: duplicate account %s", ErrInvalidChartOfAccounts, account.Key)
+		}
+		keys[account.Key] = struct{}{}
+
+		accountType, ok := types[account.Type]
+		if !ok {
+			return fmt.Errorf("%w: account %s has unknown type %q", ErrInvalidChartOfAccounts, account.Key, account.Type)
+		}
+		if code := account.code(accountType); code < accountType.CodeMin || code > accountType.CodeMax {
+			return fmt.Errorf("%w: account %s code %d is out of type %s range %d-%d", ErrInvalidChartOfAccounts,
+				account.Key, code, accountType.Name, accountType.CodeMin, accountType.CodeMax)
+		}
+		for _, code := range account.Ledgers {
+			if _, ok := LookupCurrency(code); !ok {
+				return fmt.Errorf("%w: account %s has unregistered currency %q", ErrInvalidChartOfAccounts, account.Key, code)
+			}
+		}
+	}
+	return nil
+}
+
+// resolveTypes resolves the inherited values of every type, validating the hierarchy.
+func (c *ChartOfAccounts) resolveTypes() (map[string]*resolvedAccountType, error) {
+	defined := make(map[string]AccountType, len(c.Types))
+	for _, accountType := range c.Types {
+		if accountType.Name == "" {
+			return nil, fmt.Errorf("%w: type name must not be empty", ErrInvalidChartOfAccounts)
+		}
+		if _, ok := defined[accountType.Name]; ok {
+			return nil, fmt.Errorf("%w: duplicate type %s", ErrInvalidChartOfAccounts, accountType.Name)
+		}
+		defined[accountType.Name] = accountType
+	}
+
+	resolved := make(map[string]*resolvedAccountType, len(c.Types))
+	var resolve func(name string, depth int) (*resolvedAccountType, error)
+	resolve = func(name string, depth int) (*resolvedAccountType, error) {
+		if t, ok := resolved[name]; ok {
+			return t, nil
+		}
+		if depth > len(c.Types) {
+			return nil, fmt.Errorf("%w: type %s has a parent cycle", ErrInvalidChartOfAccounts, name)
+		}
+		t := &resolvedAccountType{AccountType: defined[name]}
+		var parent *resolvedAccountType
+		if t.Parent != "" {
+			if _, ok := defined[t.Parent]; !ok {
+				return nil, fmt.Errorf("%w: type %s has unknown parent %q", ErrInvalidChartOfAccounts, name, t.Parent)
+			}
+			var err error
+			if parent, err = resolve(t.Parent, depth+1); err != nil {
+				return nil, err
+			}
+			t.parents = append([]string{parent.Name}, parent.parents...)
+			if t.NormalSide == 0 {
+				t.NormalSide = parent.NormalSide
+			}
+			if t.CodeMin == 0 && t.CodeMax == 0 {
+				t.CodeMin, t.CodeMax = parent.CodeMin, parent.CodeMax
+			}
+			if t.CodeMin < parent.CodeMin || t.CodeMax > parent.CodeMax {
+				return nil, fmt.Errorf("%w: type %s code range %d-%d is out of parent %s range %d-%d",
+					ErrInvalidChartOfAccounts, name, t.CodeMin, t.CodeMax, parent.Name, parent.CodeMin, parent.CodeMax)
+			}
+		}
+		switch {
+		case t.Flags != nil:
+			t.flags = *t.Flags
+		case parent != nil && parent.NormalSide == t.NormalSide:
+			t.flags = parent.flags
+		default:
+			t.flags = defaultFlagPolicy(t.NormalSide)
+		}
+
+		switch {
+		case t.NormalSide != NormalSideCredit && t.NormalSide != NormalSideDebit:
+			return nil, fmt.Errorf("%w: type %s has no normal side", ErrInvalidChartOfAccounts, name)
+		case t.CodeMin == 0 || t.CodeMin > t.CodeMax:
+			return nil, fmt.Errorf("%w: type %s has invalid code range %d-%d", ErrInvalidChartOfAccounts,
+				name, t.CodeMin, t.CodeMax)
+		case t.CodeMin <= uint16(AccountCategoryExpense) && t.CodeMax >= uint16(AccountCategoryControl),
+			t.CodeMin <= uint16(AccountCategoryTesting) && t.CodeMax >= uint16(AccountCategoryTesting):
+			return nil, fmt.Errorf("%w: type %s code range %d-%d overlaps the built-in account categories",
+				ErrInvalidChartOfAccounts, name, t.CodeMin, t.CodeMax)
+		case t.flags.DebitsMustNotExceedCredits && t.flags.CreditsMustNotExceedDebits:
+			return nil, fmt.Errorf("%w: type %s flags DebitsMustNotExceedCredits & CreditsMustNotExceedDebits "+
+				"are mutually exclusive", ErrInvalidChartOfAccounts, name)
+		case t.NormalSide == NormalSideDebit && !t.flags.CreditsMustNotExceedDebits:
+			return nil, fmt.Errorf("%w: debit normal type %s must have flag CreditsMustNotExceedDebits",
+				ErrInvalidChartOfAccounts, name)
+		case t.NormalSide == NormalSideCredit && t.flags.CreditsMustNotExceedDebits:
+			return nil, fmt.Errorf("%w: credit normal type %s must not have flag CreditsMustNotExceedDebits",
+				ErrInvalidChartOfAccounts, name)
+		}
+		resolved[name] = t
+		return t, nil
+	}
+	for _, accountType := range c.Types {
+		if _, err := resolve(accountType.Name, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	// Code ranges of unrelated types must not overlap, so account codes identify their type.
+	for _, a := range resolved {
+		for _, b := range resolved {
+			if a.Name >= b.Name || a.isDescendantOf(b.Name) || b.isDescendantOf(a.Name) {
+				continue
+			}
+			if a.CodeMin <= b.CodeMax && b.CodeMin <= a.CodeMax {
+				return nil, fmt.Errorf("%w: types %s and %s have overlapping code ranges", ErrInvalidChartOfAccounts,
+					a.Name, b.Name)
+			}
+		}
+	}
+	return resolved, nil
+}
+
+// isDescendantOf reports whether the type is a descendant of the named type.
+func (t *resolvedAccountType) isDescendantOf(name string) bool {
+	for _, parent := range t.parents {
+		if parent == name {
+			return true
+		}
+	}
+	return false
+}
+
+// code returns the account code, the type CodeMin if zero.
+func (a SystemAccount) code(t *resolvedAccountType) uint16 {
+	if a.Code == 0 {
+		return t.CodeMin
+	}
+	return a.Code
+}
+
+// AccountID returns the deterministic ID of the system account on the ledger.
+// The ID is derived from the namespace, key and ledger code, so it changes if the LedgerEncoder changes.
+//
+//	feeAccountID := chart.AccountID("fees.revenue", tbdb.IDR)
+func (c *ChartOfAccounts) AccountID(key string, ledger Ledger) Uint128 {
+	return DeriveID(c.Namespace, fmt.Sprintf("%s:%d", key, ledger.EncodeLedger()))
+}
+
+// NormalSide returns the normal side of the account type, zero if the type is unknown or invalid.
+func (c *ChartOfAccounts) NormalSide(typeName string) NormalSide {
+	types, err := c.resolveTypes()
+	if err != nil {
+		return 0
+	}
+	if t, ok := types[typeName]; ok {
+		return t.NormalSide
+	}
+	return 0
+}
+
+// SetChartOfAccounts sets the chart of accounts provisioned by Open, nil disables provisioning.
+// It replaces the TBDB_CHART_OF_ACCOUNTS chart, including its load error.
+func (i *Instance) SetChartOfAccounts(chart *ChartOfAccounts) { i.chart, i.chartErr = chart, nil }
+
+// ProvisionChartOfAccounts creates the system accounts of the chart on each of their ledgers.
+// It is idempotent: accounts that already exist with the same fields are reported as AccountExists,
+// returns error if any account can not be created, e.g. exists with different flags or code.
+func (i *Instance) ProvisionChartOfAccounts(chart *ChartOfAccounts) (AccountEventResults, error) {
+	if chart == nil {
+		return AccountEventResults{}, fmt.Errorf("%w: chart must not be nil", ErrInvalidChartOfAccounts)
+	}
+	if err := chart.Validate(); err != nil {
+		return AccountEventResults{}, err
+	}
+	types, _ := chart.resolveTypes()
+
+	// Build the system accounts of every ledger.
+	var (
+		accounts []CreateAccounts
+		names    []string
+	)
+	for _, account := range chart.Accounts {
+		accountType := types[account.Type]
+		for _, code := range account.Ledgers {
+			cur, _ := LookupCurrency(code)
+			accounts = append(accounts, CreateAccounts{
+				CreateAccount: CreateAccount{ID: chart.AccountID(account.Key, cur), Ledger: cur},
+				Code:          account.code(accountType),
+				Flags: AccountFlags{
+					History:                    accountType.flags.History,
+					DebitsMustNotExceedCredits: accountType.flags.DebitsMustNotExceedCredits,
+					CreditsMustNotExceedDebits: accountType.flags.CreditsMustNotExceedDebits,
+				},
+			})
+			names = append(names, account.Key+" on "+cur.Code())
+		}
+	}
+	if len(accounts) == 0 {
+		return AccountEventResults{}, nil
+	}
+
+	// Create in batches of TigerBeetleMaxBatch.
+	var (
+		result AccountEventResults
+		errs   []error
+	)
+	for start := 0; start < len(accounts); start += int(TigerBeetleMaxBatch) {
+		end := min(start+int(TigerBeetleMaxBatch), len(accounts))
+		batch, err := i.CreateAccountBatch(accounts[start:end])
+		if err != nil {
+			return AccountEventResults{}, err
+		}
+		result.SuccessCount += batch.SuccessCount
+		result.FailedCount += batch.FailedCount
+		for _, r := range batch.Results {
+			r.Index += uint32(start)
+			result.Results = append(result.Results, r)
+			if r.Err != nil {
+				errs = append(errs, fmt.Errorf("provision account %s: %w", names[r.Index], r.Err))
+			}
+		}
+	}
+	return result, errors.Join(errs...)
+}
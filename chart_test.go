@@ -0,0 +1,160 @@
+package tbdb
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/qoinlyid/qore"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+// testChartOfAccounts returns a valid chart.
+func testChartOfAccounts() ChartOfAccounts {
+	return ChartOfAccounts{
+		Namespace: Uint128FromUint64(42),
+		Types: []AccountType{
+			{Name: "assets", NormalSide: NormalSideDebit, CodeMin: 1100, CodeMax: 1999},
+			{Name: "assets.settlement", Parent: "assets", CodeMin: 1100, CodeMax: 1199},
+			{Name: "income", NormalSide: NormalSideCredit, CodeMin: 4000, CodeMax: 4999, Flags: &AccountFlagPolicy{
+				History: true, DebitsMustNotExceedCredits: true,
+			}},
+			{Name: "income.fees", Parent: "income"},
+		},
+		Accounts: []SystemAccount{
+			{Key: "settlement.bank", Type: "assets.settlement", Ledgers: []string{"IDR", "USD"}},
+			{Key: "fees.revenue", Type: "income.fees", Code: 4100, Ledgers: []string{"IDR"}},
+		},
+	}
+}
+
+func TestChartOfAccountsValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		chart   ChartOfAccounts
+		wantErr bool
+	}{
+		{"valid", testChartOfAccounts(), false},
+		{"zero namespace", modified(testChartOfAccounts(), func(c *ChartOfAccounts) { c.Namespace = Uint128{} }), true},
+		{"duplicate type", modified(testChartOfAccounts(), func(c *ChartOfAccounts) { c.Types = append(c.Types, c.Types[0]) }), true},
+		{"unknown parent", modified(testChartOfAccounts(), func(c *ChartOfAccounts) { c.Types[1].Parent = "equity" }), true},
+		{"parent cycle", modified(testChartOfAccounts(), func(c *ChartOfAccounts) { c.Types[0].Parent = "assets.settlement" }), true},
+		{"no normal side", modified(testChartOfAccounts(), func(c *ChartOfAccounts) { c.Types[0].NormalSide = 0 }), true},
+		{"out of parent range", modified(testChartOfAccounts(), func(c *ChartOfAccounts) { c.Types[1].CodeMax = 2000 }), true},
+		{"overlapping ranges", modified(testChartOfAccounts(), func(c *ChartOfAccounts) { c.Types[2].CodeMin = 1500 }), true},
+		{"built-in category range", modified(testChartOfAccounts(), func(c *ChartOfAccounts) { c.Types[0].CodeMin = 1000 }), true},
+		{"testing category range", modified(testChartOfAccounts(), func(c *ChartOfAccounts) { c.Types[2].CodeMax = 9999 }), true},
+		{"debit normal flags", modified(testChartOfAccounts(), func(c *ChartOfAccounts) {
+			c.Types[0].Flags = &AccountFlagPolicy{History: true}
+		}), true},
+		{"credit normal flags", modified(testChartOfAccounts(), func(c *ChartOfAccounts) {
+			c.Types[3].Flags = &AccountFlagPolicy{CreditsMustNotExceedDebits: true}
+		}), true},
+		{"exclusive flags", modified(testChartOfAccounts(), func(c *ChartOfAccounts) { c.Types[2].Flags.CreditsMustNotExceedDebits = true }), true},
+		{"duplicate account", modified(testChartOfAccounts(), func(c *ChartOfAccounts) { c.Accounts[1].Key = "settlement.bank" }), true},
+		{"unknown account type", modified(testChartOfAccounts(), func(c *ChartOfAccounts) { c.Accounts[0].Type = "equity" }), true},
+		{"code out of range", modified(testChartOfAccounts(), func(c *ChartOfAccounts) { c.Accounts[1].Code = 1100 }), true},
+		{"no ledgers", modified(testChartOfAccounts(), func(c *ChartOfAccounts) { c.Accounts[0].Ledgers = nil }), true},
+		{"unregistered currency", modified(testChartOfAccounts(), func(c *ChartOfAccounts) { c.Accounts[0].Ledgers = []string{"XYZ1"} }), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.chart.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidChartOfAccounts, "Validate must return error")
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+
+	chart := testChartOfAccounts()
+	assert.Equal(t, NormalSideDebit, chart.NormalSide("assets.settlement"), "child type must inherit normal side")
+	assert.Equal(t, chart.AccountID("fees.revenue", IDR), chart.AccountID("fees.revenue", IDR))
+	assert.NotEqual(t, chart.AccountID("fees.revenue", IDR), chart.AccountID("fees.revenue", USD))
+}
+
+func TestLoadChartOfAccounts(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "chart.yaml")
+	assert.NoError(t, os.WriteFile(path, []byte(`
+namespace: 6ba7b810-9dad-11d1-80b4-00c04fd430c8
+types:
+  - name: liabilities
+    normal_side: credit
+    code_min: 2000
+    code_max: 2999
+    flags:
+      history: true
+      debits_must_not_exceed_credits: true
+accounts:
+  - key: float
+    type: liabilities
+    ledgers: [IDR]
+`), 0o600))
+
+	chart, err := LoadChartOfAccounts(path)
+	assert.NoError(t, err)
+	namespace, _ := Uint128FromUUID("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	assert.Equal(t, namespace, chart.Namespace)
+	assert.Equal(t, NormalSideCredit, chart.Types[0].NormalSide)
+	assert.Equal(t, &AccountFlagPolicy{History: true, DebitsMustNotExceedCredits: true}, chart.Types[0].Flags)
+	assert.Equal(t, []string{"IDR"}, chart.Accounts[0].Ledgers)
+
+	assert.NoError(t, os.WriteFile(path, []byte("namespace: 6ba7b810-9dad-11d1-80b4-00c04fd430c8\ntypes:\n  - name: x\n    normal_side: sideways\n"), 0o600))
+	_, err = LoadChartOfAccounts(path)
+	assert.ErrorIs(t, err, ErrUnknownNormalSide)
+}
+
+func TestOpenInvalidChartOfAccounts(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	content := "TBDB_CHART_OF_ACCOUNTS: " + filepath.Join(dir, "missing.yaml") + "\n"
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	t.Setenv(qore.CONFIG_USED_KEY, path)
+	t.Cleanup(viper.Reset)
+
+	i := New()
+	assert.Error(t, i.Open(), "Open must fail on a chart of accounts that can not be loaded")
+	assert.Empty(t, defaultConfig.ChartOfAccounts, "config source must not leak into the default config")
+
+	i.SetChartOfAccounts(nil)
+	assert.NoError(t, i.chartErr, "SetChartOfAccounts must replace the load error")
+}
+
+func TestProvisionChartOfAccounts(t *testing.T) {
+	client := &fakeClient{}
+	i := &Instance{client: client}
+	chart := testChartOfAccounts()
+
+	result, err := i.ProvisionChartOfAccounts(&chart)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.SuccessCount)
+	assert.Len(t, client.accounts, 3)
+
+	fees, _ := client.account(toBinding(chart.AccountID("fees.revenue", IDR)))
+	assert.Equal(t, uint16(4100), fees.Code)
+	assert.Equal(t, uint32(IDR.EncodeLedger()), fees.Ledger)
+	assert.True(t, fees.AccountFlags().DebitsMustNotExceedCredits, "child type must inherit flags")
+	settlement, _ := client.account(toBinding(chart.AccountID("settlement.bank", USD)))
+	assert.Equal(t, uint16(1100), settlement.Code, "zero code must use type CodeMin")
+	assert.Equal(t, types.AccountFlags{History: true, CreditsMustNotExceedDebits: true}, settlement.AccountFlags(),
+		"root type flags must default from the normal side")
+	account, err := toAccount(settlement, AccountLookup{})
+	assert.NoError(t, err)
+	assert.Equal(t, NormalSideDebit, account.NormalSide())
+
+	// Provisioning again is idempotent.
+	result, err = i.ProvisionChartOfAccounts(&chart)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.SuccessCount)
+	assert.Equal(t, types.AccountExists, result.Results[0].Result)
+	assert.Len(t, client.accounts, 3)
+
+	// Changed code conflicts with the existing account.
+	changed := modified(testChartOfAccounts(), func(c *ChartOfAccounts) { c.Accounts[1].Code = 4200 })
+	_, err = i.ProvisionChartOfAccounts(&changed)
+	assert.ErrorContains(t, err, "fees.revenue on IDR")
+}
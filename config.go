@@ -1,11 +1,14 @@
 package tbdb
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/qoinlyid/qore"
@@ -24,6 +27,14 @@ type Config struct {
 
 	// Addresses defines TigerBeetle nodes address. Use comma separated to set multi nodes.
 	Addresses string `json:"TBDB_ADDRESSES" mapstructure:"TBDB_ADDRESSES"`
+
+	// Currencies defines custom currencies or tokens to be registered, see RegisterCurrency.
+	// In env source, use JSON array or comma separated "CODE:DECIMAL", e.g. "DOGE:8,SHIB:18".
+	Currencies []CurrencyDefinition `json:"TBDB_CURRENCIES" mapstructure:"TBDB_CURRENCIES"`
+
+	// ChartOfAccounts defines the chart of accounts file (YAML, JSON or TOML) provisioned on Open,
+	// see ChartOfAccounts.
+	ChartOfAccounts string `json:"TBDB_CHART_OF_ACCOUNTS" mapstructure:"TBDB_CHART_OF_ACCOUNTS"`
 }
 
 // Default config.
@@ -34,7 +45,8 @@ var defaultConfig = &Config{
 // Load config.
 func loadConfig() *Config {
 	var e error
-	config := defaultConfig
+	// Unmarshal into a copy, so values of a config source do not leak into the next load.
+	config := *defaultConfig
 
 	// Get used config from OS env.
 	configSource := os.Getenv(qore.CONFIG_USED_KEY)
@@ -46,7 +58,7 @@ func loadConfig() *Config {
 
 	switch strings.ToUpper(configSource) {
 	case "OS":
-		if err := viper.Unmarshal(&config); err != nil {
+		if err := viper.Unmarshal(&config, viper.DecodeHook(currencyDefinitionsHook)); err != nil {
 			e = errors.Join(fmt.Errorf("failed to parse OS env value to config: %w", err))
 		}
 	default:
@@ -58,16 +70,17 @@ func loadConfig() *Config {
 			if err := viper.ReadInConfig(); err != nil {
 				e = errors.Join(fmt.Errorf("failed to read env file %s: %w", configSource, err))
 			} else {
-				if err := viper.Unmarshal(&config); err != nil {
+				if err := viper.Unmarshal(&config, viper.DecodeHook(currencyDefinitionsHook)); err != nil {
 					e = errors.Join(fmt.Errorf("failed to parse env file %s value to config: %w", configSource, err))
 				}
 			}
 		case ".json", ".yml", ".yaml", ".toml":
 			viper.SetConfigFile(configSource)
+			viper.SetConfigType(strings.TrimPrefix(ext, "."))
 			if err := viper.ReadInConfig(); err != nil {
 				e = errors.Join(fmt.Errorf("failed to read config file %s: %w", configSource, err))
 			} else {
-				if err := viper.Unmarshal(&config); err != nil {
+				if err := viper.Unmarshal(&config, viper.DecodeHook(currencyDefinitionsHook)); err != nil {
 					e = errors.Join(fmt.Errorf("failed to parse config file %s value to config: %w", configSource, err))
 				}
 			}
@@ -82,5 +95,46 @@ func loadConfig() *Config {
 		config.DependencyPriority = defaultConfig.DependencyPriority
 	}
 	config.clusterIDTB = types.ToUint128(config.ClusterID)
-	return config
+
+	// Register config-defined currencies.
+	for _, def := range config.Currencies {
+		cur, err := NewCurrencyFromDefinition(def)
+		if err == nil {
+			err = RegisterCurrency(cur)
+		}
+		if err != nil {
+			log.Printf("dependency config - failed to register currency %s: %s\n", def.Code, err.Error())
+		}
+	}
+	return &config
+}
+
+// currencyDefinitionsHook decodes env string value of TBDB_CURRENCIES,
+// as JSON array or comma separated "CODE:DECIMAL".
+func currencyDefinitionsHook(from, to reflect.Type, data any) (any, error) {
+	if from.Kind() != reflect.String || to != reflect.TypeOf([]CurrencyDefinition{}) {
+		return data, nil
+	}
+	raw := strings.TrimSpace(data.(string))
+	if strings.HasPrefix(raw, "[") {
+		var defs []CurrencyDefinition
+		if err := json.Unmarshal([]byte(raw), &defs); err != nil {
+			return nil, err
+		}
+		return defs, nil
+	}
+
+	var defs []CurrencyDefinition
+	for item := range strings.SplitSeq(raw, ",") {
+		if len(strings.TrimSpace(item)) == 0 {
+			continue
+		}
+		code, decimal, _ := strings.Cut(item, ":")
+		val, err := strconv.ParseUint(strings.TrimSpace(decimal), 10, 8)
+		if err != nil {
+			return nil, fmt.Errorf("invalid currency %q: %w", item, err)
+		}
+		defs = append(defs, CurrencyDefinition{Code: strings.TrimSpace(code), Decimal: uint8(val)})
+	}
+	return defs, nil
 }
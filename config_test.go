@@ -0,0 +1,54 @@
+package tbdb
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/qoinlyid/qore"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadConfigCurrencies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "TBDB_CURRENCIES:\n  - code: CFGT\n    decimal: 9\n    name: Config Token\n    symbol: CT\n"
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	t.Setenv(qore.CONFIG_USED_KEY, path)
+	t.Cleanup(viper.Reset)
+
+	i := New()
+	assert.NotEmpty(t, i.cfg.Currencies, "Currencies must be loaded")
+	cur, ok := LookupCurrency("CFGT")
+	assert.True(t, ok, "config-defined currency must be registered")
+	assert.Equal(t, uint8(9), cur.Decimal())
+	assert.Equal(t, "Config Token", cur.Name())
+	assert.Equal(t, "CT", cur.Symbol())
+}
+
+func TestCurrencyDefinitionsHook(t *testing.T) {
+	to := reflect.TypeOf([]CurrencyDefinition{})
+	tests := []struct {
+		name    string
+		input   string
+		want    []CurrencyDefinition
+		wantErr bool
+	}{
+		{"compact", "DOGE:8, SHIB:18", []CurrencyDefinition{{Code: "DOGE", Decimal: 8}, {Code: "SHIB", Decimal: 18}}, false},
+		{"json", `[{"code":"DOGE","decimal":8,"name":"Dogecoin"}]`, []CurrencyDefinition{{Code: "DOGE", Decimal: 8, Name: "Dogecoin"}}, false},
+		{"invalid decimal", "DOGE:x", nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := currencyDefinitionsHook(reflect.TypeOf(""), to, tt.input)
+			if tt.wantErr {
+				assert.Error(t, err, "Expected error for input %q", tt.input)
+				return
+			}
+			assert.NoError(t, err, "Unexpected error for input %q", tt.input)
+			assert.Equal(t, tt.want, got, "currencyDefinitionsHook(%q) returned unexpected result", tt.input)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package tbdb
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// currencyRegistry maps ledger codes, currency codes and ISO 4217 numeric codes to the registered currencies.
+type currencyRegistry struct {
+	mu        sync.RWMutex
+	byLedger  map[LedgerCode]*Currency
+	byCode    map[string]*Currency
+	byNumeric map[uint16]*Currency
+}
+
+// currencies is the package registry, pre-filled with the built-in currencies and the ISO 4217 table.
+var currencies = newCurrencyRegistry(VND, IDR, MYR, SGD, THB, PHP, USD, EUR, USDT, BTC, BNB, ETH)
+
+// newCurrencyRegistry creates a registry of the given currencies followed by the ISO 4217 table,
+// the built-ins never collide and take precedence over the table entries of the same code.
+func newCurrencyRegistry(builtins ...*Currency) *currencyRegistry {
+	r := &currencyRegistry{
+		byLedger: make(map[LedgerCode]*Currency, len(builtins)+len(iso4217)),
+		byCode:   make(map[string]*Currency, len(builtins)+len(iso4217)),
+		// Only the ISO 4217 table has numeric codes.
+		byNumeric: make(map[uint16]*Currency, len(iso4217)),
+	}
+	for _, cur := range builtins {
+		r.add(cur.EncodeLedger(), cur)
+	}
+	for _, def := range iso4217 {
+		if _, ok := r.byCode[def.Code]; ok {
+			continue
+		}
+		cur := newCurrencyFromDefinition(def)
+		r.add(cur.EncodeLedger(), cur)
+	}
+	return r
+}
+
+// add indexes the currency by ledger, code and numeric code, if any.
+func (r *currencyRegistry) add(ledger LedgerCode, cur *Currency) {
+	r.byLedger[ledger] = cur
+	r.byCode[cur.code] = cur
+	if cur.numericCode != 0 {
+		r.byNumeric[cur.numericCode] = cur
+	}
+}
+
+// register adds the currency, registering the same code and decimal again is a no-op.
+func (r *currencyRegistry) register(cur *Currency) error {
+	if err := validateDecimal(cur.decimal); err != nil {
+		return fmt.Errorf("currency %s: %w", cur.code, err)
+	}
+	ledger, err := CurrentLedgerEncoder().EncodeLedger(cur.code, cur.decimal)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if existing, ok := r.byCode[cur.code]; ok {
+		if existing.decimal != cur.decimal {
+			return fmt.Errorf("%w: %s with %d decimals", ErrCurrencyCodeRegistered, cur.code, existing.decimal)
+		}
+		return nil
+	}
+	if existing, ok := r.byLedger[ledger]; ok {
+		return fmt.Errorf("%w: %s and %s share ledger %d", ErrLedgerCodeCollision, cur.code, existing.code, ledger)
+	}
+	if existing, ok := r.byNumeric[cur.numericCode]; ok && cur.numericCode != 0 {
+		return fmt.Errorf("%w: %s and %s share numeric code %03d", ErrNumericCodeRegistered, cur.code, existing.code,
+			cur.numericCode)
+	}
+	r.add(ledger, cur)
+	return nil
+}
+
+// RegisterCurrency registers a custom currency, so its ledger can be resolved by ParseLedgerCode.
+// It returns ErrCurrencyCodeRegistered if the code is registered with another decimal precision,
+// ErrLedgerCodeCollision if another currency already encodes into the same ledger code,
+// or ErrNumericCodeRegistered if another currency has the same numeric code.
+//
+//	doge, _ := tbdb.NewCurrency("DOGE", 8)
+//	if err := tbdb.RegisterCurrency(doge); err != nil {
+//		log.Fatal(err)
+//	}
+func RegisterCurrency(cur *Currency) error {
+	if cur == nil {
+		return ErrMonetaryMustNotBeNil
+	}
+	return currencies.register(cur)
+}
+
+// LookupCurrency returns the registered currency of the code, e.g. "IDR".
+func LookupCurrency(code string) (*Currency, bool) {
+	currencies.mu.RLock()
+	defer currencies.mu.RUnlock()
+	cur, ok := currencies.byCode[strings.ToUpper(strings.TrimSpace(code))]
+	return cur, ok
+}
+
+// ParseLedgerCode resolves the currency of a raw TigerBeetle ledger, e.g. Account.Ledger.
+// Registered currencies are resolved first, then reversible ledgers are decoded with the package LedgerEncoder,
+// e.g. codes of up to 3 characters with HashLedgerEncoder, and tenant ledgers; hashed codes must be registered
+// with RegisterCurrency.
+func ParseLedgerCode(ledger uint32) (*Currency, error) {
+	code := LedgerCode(ledger)
+	currencies.mu.RLock()
+	cur, ok := currencies.byLedger[code]
+	currencies.mu.RUnlock()
+	if ok {
+		return cur, nil
+	}
+
+	// Decode reversible ledger.
+	if currencyCode, decimal, err := CurrentLedgerEncoder().DecodeLedger(code); err == nil {
+		if cur = newCurrency(currencyCode, decimal); cur.EncodeLedger() == code {
+			return cur, nil
+		}
+	}
+
+	// Decode tenant ledger.
+	if tenantLedger, err := ParseTenantLedger(ledger); err == nil {
+		return tenantLedger.Currency, nil
+	}
+	return nil, fmt.Errorf("%w: %d", ErrUnknownLedgerCode, ledger)
+}
+
+// lookupNumericCode returns the registered currency of the ISO 4217 numeric code.
+func lookupNumericCode(numeric uint16) (*Currency, bool) {
+	currencies.mu.RLock()
+	defer currencies.mu.RUnlock()
+	cur, ok := currencies.byNumeric[numeric]
+	return cur, ok
+}
+
+// registeredCurrencies returns every registered currency sorted by code.
+func registeredCurrencies() []*Currency {
+	currencies.mu.RLock()
+	defer currencies.mu.RUnlock()
+	list := make([]*Currency, 0, len(currencies.byCode))
+	for _, cur := range currencies.byCode {
+		list = append(list, cur)
+	}
+	slices.SortFunc(list, func(a, b *Currency) int { return strings.Compare(a.code, b.code) })
+	return list
+}
+
+// monetaryOf returns the Amount implementation of a raw TigerBeetle ledger.
+func monetaryOf(ledger uint32) (Amount, error) {
+	cur, err := ParseLedgerCode(ledger)
+	if err != nil {
+		return nil, err
+	}
+	return cur.NewMonetary(), nil
+}
@@ -0,0 +1,94 @@
+package tbdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLedgerCode(t *testing.T) {
+	doge := newCurrency("DOGE", 8)
+	assert.NoError(t, RegisterCurrency(doge))
+
+	tests := []struct {
+		name    string
+		ledger  uint32
+		want    *Currency
+		wantErr error
+	}{
+		{"built-in", uint32(IDR.EncodeLedger()), IDR, nil},
+		{"built-in hashed", uint32(USDT.EncodeLedger()), USDT, nil},
+		{"registered hashed", uint32(doge.EncodeLedger()), doge, nil},
+		{"unregistered direct", uint32(newCurrency("AB1", 0).EncodeLedger()), newCurrency("AB1", 0), nil},
+		{"unregistered hashed", uint32(newCurrency("SHIB", 18).EncodeLedger()), nil, ErrUnknownLedgerCode},
+		{"invalid decimal part", 42, nil, ErrUnknownLedgerCode},
+		{"invalid char value", 102363636, nil, ErrUnknownLedgerCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLedgerCode(tt.ledger)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr, "ParseLedgerCode(%d) must return error", tt.ledger)
+				return
+			}
+			assert.NoError(t, err, "Unexpected error for ledger %d", tt.ledger)
+			assert.Equal(t, tt.want, got, "ParseLedgerCode(%d) returned unexpected result", tt.ledger)
+		})
+	}
+}
+
+func TestRegisterCurrency(t *testing.T) {
+	assert.NoError(t, RegisterCurrency(newCurrency("IDR", 2)), "same code and decimal must be a no-op")
+	assert.ErrorIs(t, RegisterCurrency(newCurrency("IDR", 0)), ErrCurrencyCodeRegistered)
+	assert.ErrorIs(t, RegisterCurrency(nil), ErrMonetaryMustNotBeNil)
+
+	// "US$" encodes into the same ledger as "US".
+	assert.NoError(t, RegisterCurrency(newCurrency("US", 2)))
+	assert.ErrorIs(t, RegisterCurrency(newCurrency("US$", 2)), ErrLedgerCodeCollision)
+
+	// A numeric code resolves a single tenant ledger currency.
+	rupiah := newCurrencyFromDefinition(CurrencyDefinition{Code: "RPH", Decimal: 2, NumericCode: 360})
+	assert.ErrorIs(t, RegisterCurrency(rupiah), ErrNumericCodeRegistered)
+	_, ok := LookupCurrency("RPH")
+	assert.False(t, ok, "rejected currency must not be registered")
+
+	cur, ok := LookupCurrency(" usd ")
+	assert.True(t, ok)
+	assert.Equal(t, USD, cur)
+	_, ok = LookupCurrency("XYZ")
+	assert.False(t, ok)
+}
+
+func TestISO4217(t *testing.T) {
+	tests := []struct {
+		code        string
+		decimal     uint8
+		numericCode uint16
+		name        string
+	}{
+		{"IDR", 2, 360, "Rupiah"},
+		{"JPY", 0, 392, "Yen"},
+		{"KWD", 3, 414, "Kuwaiti Dinar"},
+		{"CLF", 4, 990, "Unidad de Fomento"},
+		{"VND", 0, 704, "Dong"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.code, func(t *testing.T) {
+			cur, ok := LookupCurrency(tt.code)
+			assert.True(t, ok, "LookupCurrency(%q) must find the currency", tt.code)
+			assert.Equal(t, tt.decimal, cur.Decimal())
+			assert.Equal(t, tt.numericCode, cur.NumericCode())
+			assert.Equal(t, tt.name, cur.Name())
+
+			got, err := ParseLedgerCode(uint32(cur.EncodeLedger()))
+			assert.NoError(t, err)
+			assert.Same(t, cur, got, "ParseLedgerCode must resolve the registered currency")
+		})
+	}
+
+	cur, _ := LookupCurrency("IDR")
+	assert.Same(t, IDR, cur, "built-in currency must take precedence")
+	assert.Equal(t, "Rp", IDR.Symbol())
+}
@@ -0,0 +1,123 @@
+package tbdb
+
+import (
+	"math/bits"
+	"strconv"
+	"strings"
+)
+
+// mulAdd64 returns u*m + a, reporting whether the result overflows 128 bits.
+func mulAdd64(u Uint128, m, a uint64) (Uint128, bool) {
+	hiCarry, hi := bits.Mul64(u.Hi, m)
+	loHi, lo := bits.Mul64(u.Lo, m)
+	hi, carry := bits.Add64(hi, loHi, 0)
+	if hiCarry != 0 || carry != 0 {
+		return Uint128{}, true
+	}
+	lo, carry = bits.Add64(lo, a, 0)
+	hi, carry = bits.Add64(hi, 0, carry)
+	if carry != 0 {
+		return Uint128{}, true
+	}
+	return Uint128{Hi: hi, Lo: lo}, false
+}
+
+// decimalDigits returns the base-10 digits of u without leading zeros.
+func decimalDigits(u Uint128) string {
+	if u.Hi == 0 {
+		return strconv.FormatUint(u.Lo, 10)
+	}
+
+	// Split into 10^19 chunks, least significant first, each chunk is zero padded except the leading one.
+	var buf [39]byte
+	pos := len(buf)
+	for {
+		var chunk uint64
+		u, chunk = u.QuoRem64(1e19)
+		digits := strconv.AppendUint(buf[:0:0], chunk, 10)
+		pos -= len(digits)
+		copy(buf[pos:], digits)
+		if u.IsZero() {
+			break
+		}
+		for pad := 19 - len(digits); pad > 0; pad-- {
+			pos--
+			buf[pos] = '0'
+		}
+	}
+	return string(buf[pos:])
+}
+
+// parseDecimal parses a plain decimal string, e.g. "12345.6789", into minor units scaled by 10^decimal.
+// Only an optional leading '+', digits and a single '.' are accepted, the value never goes through float64.
+// Fraction digits beyond the decimal precision are rounded according to mode.
+func parseDecimal(s string, decimal uint8, mode RoundingMode) (Uint128, error) {
+	s = strings.TrimSpace(s)
+	s = strings.TrimPrefix(s, "+")
+	if strings.HasPrefix(s, "-") {
+		return Uint128{}, ErrNegativeAmount
+	}
+	intPart, fracPart, hasPoint := strings.Cut(s, ".")
+	if len(intPart) == 0 || (hasPoint && len(fracPart) == 0) {
+		return Uint128{}, ErrInvalidDecimal
+	}
+
+	// Accumulate integer digits then the kept fraction digits, padded up to the decimal precision.
+	var (
+		val      Uint128
+		overflow bool
+	)
+	for idx := 0; idx < len(intPart)+int(decimal); idx++ {
+		var digit byte
+		switch {
+		case idx < len(intPart):
+			digit = intPart[idx]
+		case idx-len(intPart) < len(fracPart):
+			digit = fracPart[idx-len(intPart)]
+		default:
+			digit = '0'
+		}
+		if digit < '0' || digit > '9' {
+			return Uint128{}, ErrInvalidDecimal
+		}
+		if val, overflow = mulAdd64(val, 10, uint64(digit-'0')); overflow {
+			return Uint128{}, ErrUint128Overflow
+		}
+	}
+	if len(fracPart) <= int(decimal) {
+		return val, nil
+	}
+
+	// Dropped fraction digits decide the rounding.
+	dropped := fracPart[decimal:]
+	hasRemainder, cmpHalf := false, -1
+	for idx := 0; idx < len(dropped); idx++ {
+		digit := dropped[idx]
+		if digit < '0' || digit > '9' {
+			return Uint128{}, ErrInvalidDecimal
+		}
+		if digit != '0' {
+			hasRemainder = true
+		}
+		switch {
+		case idx > 0:
+			if digit != '0' && cmpHalf == 0 {
+				cmpHalf = 1
+			}
+		case digit > '5':
+			cmpHalf = 1
+		case digit == '5':
+			cmpHalf = 0
+		}
+	}
+	up, err := mode.roundUp(val.Lo&1 == 1, hasRemainder, cmpHalf)
+	if err != nil {
+		return Uint128{}, err
+	}
+	if up {
+		if val, overflow = mulAdd64(val, 1, 1); overflow {
+			return Uint128{}, ErrUint128Overflow
+		}
+	}
+	return val, nil
+}
@@ -0,0 +1,171 @@
+package tbdb
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseDecimal(t *testing.T) {
+	maxUint128 := Uint128{Hi: ^uint64(0), Lo: ^uint64(0)}
+	eth, _ := new(big.Int).SetString("12345678901234567890123", 10)
+	ethVal, _ := Uint128FromBigInt(eth)
+
+	tests := []struct {
+		name    string
+		input   string
+		decimal uint8
+		mode    RoundingMode
+		want    Uint128
+		wantErr error
+	}{
+		{"integer", "20000", 2, RoundingUnnecessary, Uint128FromUint64(2_000_000), nil},
+		{"fraction", "20000.5", 2, RoundingUnnecessary, Uint128FromUint64(2_000_050), nil},
+		{"plus sign and spaces", " +1.25 ", 2, RoundingUnnecessary, Uint128FromUint64(125), nil},
+		{"no decimals", "15000", 0, RoundingUnnecessary, Uint128FromUint64(15_000), nil},
+		{"eth 18 decimals", "12345.678901234567890123", 18, RoundingUnnecessary, ethVal, nil},
+		{"max uint128", "340282366920938463463374607431768211455", 0, RoundingUnnecessary, maxUint128, nil},
+		{"overflow", "340282366920938463463374607431768211456", 0, RoundingUnnecessary, Uint128{}, ErrUint128Overflow},
+		{"strict rejects precision", "1.005", 2, RoundingUnnecessary, Uint128{}, ErrRoundingNecessary},
+		{"strict accepts trailing zeros", "1.5000", 2, RoundingUnnecessary, Uint128FromUint64(150), nil},
+		{"half up tie", "1.005", 2, RoundingHalfUp, Uint128FromUint64(101), nil},
+		{"half down tie", "1.005", 2, RoundingHalfDown, Uint128FromUint64(100), nil},
+		{"half down above tie", "1.00501", 2, RoundingHalfDown, Uint128FromUint64(101), nil},
+		{"half even tie to even", "1.005", 2, RoundingHalfEven, Uint128FromUint64(100), nil},
+		{"half even tie from odd", "1.015", 2, RoundingHalfEven, Uint128FromUint64(102), nil},
+		{"ceiling", "1.001", 2, RoundingCeiling, Uint128FromUint64(101), nil},
+		{"floor", "1.009", 2, RoundingFloor, Uint128FromUint64(100), nil},
+		{"truncate", "1.009", 2, RoundingTruncate, Uint128FromUint64(100), nil},
+		{"negative", "-1", 2, RoundingUnnecessary, Uint128{}, ErrNegativeAmount},
+		{"empty", "", 2, RoundingUnnecessary, Uint128{}, ErrInvalidDecimal},
+		{"missing integer", ".5", 2, RoundingUnnecessary, Uint128{}, ErrInvalidDecimal},
+		{"missing fraction", "5.", 2, RoundingUnnecessary, Uint128{}, ErrInvalidDecimal},
+		{"thousand separator", "1,000", 2, RoundingUnnecessary, Uint128{}, ErrInvalidDecimal},
+		{"exponent", "1e3", 2, RoundingUnnecessary, Uint128{}, ErrInvalidDecimal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseDecimal(tt.input, tt.decimal, tt.mode)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr, "parseDecimal(%q) must return error", tt.input)
+				return
+			}
+			assert.NoError(t, err, "Unexpected error for input %q", tt.input)
+			assert.Equal(t, tt.want, got, "parseDecimal(%q) returned unexpected result", tt.input)
+		})
+	}
+}
+
+func TestNewAmountFromString(t *testing.T) {
+	amount, err := IDR.NewAmountFromString("20000.50")
+	assert.NoError(t, err)
+	assert.Equal(t, Uint128FromUint64(2_000_050), amount.ToUint128FromValue())
+
+	_, err = IDR.NewAmountFromString("20000.505")
+	assert.ErrorIs(t, err, ErrRoundingNecessary)
+
+	amount, err = IDR.ParseAmount("20000.505", RoundingHalfEven)
+	assert.NoError(t, err)
+	assert.Equal(t, Uint128FromUint64(2_000_050), amount.ToUint128FromValue())
+
+	amount = USD.NewAmountFromMinorUnits(Uint128FromUint64(150))
+	assert.Equal(t, 1.5, amount.Uint128ToFloat64())
+}
+
+func TestDecimalDigits(t *testing.T) {
+	tests := []struct {
+		name  string
+		input Uint128
+		want  string
+	}{
+		{"zero", Uint128{}, "0"},
+		{"uint64", Uint128FromUint64(2_000_050), "2000050"},
+		{"max uint64 plus one", Uint128{Hi: 1}, "18446744073709551616"},
+		{"chunk padding", Uint128{Hi: 0x36, Lo: 0x35c9adc5dea00000}, "1000000000000000000000"},
+		{"max uint128", Uint128{Hi: ^uint64(0), Lo: ^uint64(0)}, "340282366920938463463374607431768211455"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, decimalDigits(tt.input), "decimalDigits(%v) returned unexpected result", tt.input)
+		})
+	}
+}
+
+func TestAmountFormat(t *testing.T) {
+	maxETH, _ := ETH.NewAmountFromString("340282366920938463463.374607431768211455")
+
+	tests := []struct {
+		name   string
+		amount Amount
+		opts   FormatOptions
+		want   string
+	}{
+		{"default", IDR.NewAmountFromMinorUnits(Uint128FromUint64(2_000_050)), FormatOptions{}, "IDR 20,000.50"},
+		{"zero", USD.NewMonetary(), FormatOptions{}, "USD 0.00"},
+		{"small fraction", USD.NewAmountFromMinorUnits(Uint128FromUint64(5)), FormatOptions{}, "USD 0.05"},
+		{"no decimals", VND.NewAmountFromMinorUnits(Uint128FromUint64(1_250_000)), FormatOptions{}, "VND 1,250,000"},
+		{"trim zeros", BTC.NewAmountFromMinorUnits(Uint128FromUint64(150_000_000)), FormatOptions{TrimTrailingZeros: true}, "BTC 1.5"},
+		{"trim point", IDR.NewAmountFromMinorUnits(Uint128FromUint64(2_000_000)), FormatOptions{TrimTrailingZeros: true}, "IDR 20,000"},
+		{"hide code", IDR.NewAmountFromMinorUnits(Uint128FromUint64(2_000_050)), FormatOptions{HideCode: true}, "20,000.50"},
+		{"negative", IDR.NewAmountFromMinorUnits(Uint128FromUint64(2_000_050)), FormatOptions{Negative: true}, "IDR -20,000.50"},
+		{"negative zero", IDR.NewMonetary(), FormatOptions{Negative: true}, "IDR 0.00"},
+		{"plus sign", IDR.NewAmountFromMinorUnits(Uint128FromUint64(100)), FormatOptions{PlusSign: true}, "IDR +1.00"},
+		{"max uint128", maxETH, FormatOptions{}, "ETH 340,282,366,920,938,463,463.374607431768211455"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.amount.Format(tt.opts), "Format returned unexpected result")
+		})
+	}
+	assert.Equal(t, "IDR 20,000.50", IDR.NewAmountFromMinorUnits(Uint128FromUint64(2_000_050)).Uint128ToString())
+	var amount Amount = IDR.NewAmountFromMinorUnits(Uint128FromUint64(125_000_000))
+	assert.Equal(t, "Rp 1.250.000,00", amount.FormatWith(LocaleIdID, FormatOptions{}))
+}
+
+func TestHighPrecisionCurrency(t *testing.T) {
+	_, err := NewCurrency("HP39", 39)
+	assert.ErrorIs(t, err, ErrUnsupportedDecimal, "NewCurrency must reject precision beyond Uint128")
+	assert.ErrorIs(t, RegisterCurrency(newCurrency("HP39", 39)), ErrUnsupportedDecimal,
+		"RegisterCurrency must reject precision beyond Uint128")
+
+	tests := []struct {
+		name    string
+		decimal uint8
+		value   float64
+	}{
+		{"20 decimals", 20, 1.5},
+		{"24 decimals", 24, 123.25},
+		{"30 decimals", 30, 2},
+		{"38 decimals", 38, 3},
+		{"beyond uint64", 6, 1e20},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cur, err := NewCurrency("HP", tt.decimal)
+			assert.NoError(t, err)
+
+			got, err := cur.NewAmountFromFloat64(tt.value).Float64ToUint128()
+			assert.NoError(t, err)
+			assert.False(t, got.IsUint64(), "Float64ToUint128 must scale beyond uint64")
+			assert.Equal(t, tt.value*math.Pow10(int(tt.decimal)), got.Float64(),
+				"Float64ToUint128 returned unexpected result")
+
+			float := cur.NewAmountFromMinorUnits(got).Uint128ToFloat64()
+			assert.InEpsilon(t, tt.value, float, 1e-15, "Uint128ToFloat64 returned unexpected result")
+		})
+	}
+
+	_, err = ETH.NewAmountFromFloat64(1e21).Float64ToUint128()
+	assert.ErrorIs(t, err, ErrUint128Overflow, "Float64ToUint128 must reject values beyond Uint128")
+
+	cur, _ := NewCurrency("HP", 30)
+	amount, err := cur.NewAmountFromString("1234567.000000000000000000000000000001")
+	assert.NoError(t, err)
+	assert.Equal(t, "HP 1,234,567.000000000000000000000000000001", amount.Uint128ToString())
+}
@@ -12,6 +12,18 @@ var (
 	ErrHexTooLong          = errors.New("hex too long")
 	ErrNegativeOrNilBigInt = errors.New("negative or nil big.Int")
 	ErrBigIntOverflow      = errors.New("big.Int overflows")
+	ErrUint128Overflow     = errors.New("uint128 overflows")
+	ErrUnsupportedScan     = errors.New("unsupported scan source type")
+	ErrUnknownEncoding     = errors.New("unknown uint128 encoding")
+	ErrInvalidUUID         = errors.New("invalid uuid")
+
+	// Amounts.
+	ErrInvalidDecimal      = errors.New("invalid decimal string")
+	ErrNegativeAmount      = errors.New("amount must not be negative")
+	ErrRoundingNecessary   = errors.New("rounding necessary")
+	ErrUnknownRoundingMode = errors.New("unknown rounding mode")
+	ErrDivisionByZero      = errors.New("division by zero")
+	ErrZeroAmount          = errors.New("amount must not be zero")
 
 	// Operations.
 	ErrMonetaryMustNotBeNil       = errors.New("account monetary must not be nil")
@@ -22,4 +34,51 @@ var (
 	ErrAccountIDMustNotBeIntMax   = errors.New("account id must not be 2^128 - 1")
 	ErrTimeMinMustNotBeZero       = errors.New("account transfer filter time min must not be zero")
 	ErrTimeMaxMustNotBeZero       = errors.New("account transfer filter time max must not be zero")
+	ErrAccountNotFound            = errors.New("account not found")
+	ErrLedgerMustNotBeNil         = errors.New("ledger must not be nil")
+	ErrTransferPreflight          = errors.New("transfer preflight failed")
+	ErrLedgerMismatch             = errors.New("ledger mismatch")
+	ErrDuplicateAccountLookup     = errors.New("account looked up with different monetaries")
+
+	// Currencies.
+	ErrUnknownLedgerCode      = errors.New("unknown ledger code")
+	ErrCurrencyCodeRegistered = errors.New("currency code already registered")
+	ErrLedgerCodeCollision    = errors.New("ledger code collision")
+	ErrNumericCodeRegistered  = errors.New("numeric code already registered")
+	ErrInvalidCurrencyCode    = errors.New("invalid currency code")
+	ErrUnsupportedDecimal     = errors.New("unsupported decimal precision")
+
+	// Tenants.
+	ErrInvalidTenant  = errors.New("invalid tenant")
+	ErrTenantMismatch = errors.New("tenant mismatch")
+
+	// Chart of accounts.
+	ErrUnknownNormalSide      = errors.New("unknown normal side")
+	ErrInvalidChartOfAccounts = errors.New("invalid chart of accounts")
+
+	// User data.
+	ErrInvalidUserDataTag = errors.New("invalid user data tag")
+	ErrUserDataOverflow   = errors.New("user data overflows")
+
+	// Statements.
+	ErrStatementUnreconciled  = errors.New("statement row can not be reconciled")
+	ErrUnknownStatementFormat = errors.New("unknown statement format")
+	ErrAccountWithoutHistory  = errors.New("account has no history flag")
+
+	// Balance series.
+	ErrUnknownBalanceInterval    = errors.New("unknown balance interval")
+	ErrInvalidBalanceSeriesRange = errors.New("balance series from must not be after to")
+
+	// Wallets.
+	ErrInvalidWallet  = errors.New("invalid wallet")
+	ErrWalletMismatch = errors.New("wallet account mismatch")
+
+	// Fees.
+	ErrUnknownFeeBearer = errors.New("unknown fee bearer")
+	ErrFeeTierNotFound  = errors.New("no fee tier covers the amount")
+	ErrFeeExceedsAmount = errors.New("fee exceeds the base amount")
+
+	// Allocations.
+	ErrAllocationNoRatio       = errors.New("allocation needs at least one non-zero ratio")
+	ErrUnknownAllocationPolicy = errors.New("unknown allocation policy")
 )
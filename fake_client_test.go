@@ -0,0 +1,244 @@
+package tbdb
+
+import (
+	"maps"
+	"slices"
+
+	tb "github.com/tigerbeetle/tigerbeetle-go"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+// fakeClient serves accounts, transfers & historical balances, in timestamp order, from memory with the filter
+// range, order & limit, any other call panics. Created accounts & transfers are stored with TigerBeetle's linked
+// chain & exists semantics.
+type fakeClient struct {
+	tb.Client
+	accounts  []types.Account
+	transfers []types.Transfer
+	// balances are the historical balances of the account filters, a single account.
+	balances []types.AccountBalance
+	// pages counts the GetAccountTransfers requests.
+	pages int
+	// race is stored before the next CreateAccounts, to simulate a concurrent creator.
+	race []types.Account
+	// calls counts the CreateAccounts & CreateTransfers requests.
+	calls int
+}
+
+// modified returns the fixture modified by fn, if not nil.
+func modified[T any](fixture T, fn func(*T)) T {
+	if fn != nil {
+		fn(&fixture)
+	}
+	return fixture
+}
+
+func (c *fakeClient) CreateAccounts(accounts []types.Account) ([]types.AccountEventResult, error) {
+	c.calls++
+	c.accounts, c.race = append(c.accounts, c.race...), nil
+
+	failed := fakeCreate(&c.accounts, accounts, func(a types.Account) bool { return a.AccountFlags().Linked },
+		func(a types.Account) types.CreateAccountResult {
+			e, ok := c.account(a.ID)
+			switch {
+			case !ok:
+				return types.AccountOK
+			case a.Flags != e.Flags:
+				return types.AccountExistsWithDifferentFlags
+			case a.UserData128 != e.UserData128:
+				return types.AccountExistsWithDifferentUserData128
+			case a.UserData64 != e.UserData64:
+				return types.AccountExistsWithDifferentUserData64
+			case a.UserData32 != e.UserData32:
+				return types.AccountExistsWithDifferentUserData32
+			case a.Ledger != e.Ledger:
+				return types.AccountExistsWithDifferentLedger
+			case a.Code != e.Code:
+				return types.AccountExistsWithDifferentCode
+			}
+			return types.AccountExists
+		},
+		types.AccountOK, types.AccountLinkedEventFailed, types.AccountLinkedEventChainOpen,
+	)
+	results := make([]types.AccountEventResult, 0, len(failed))
+	for _, idx := range slices.Sorted(maps.Keys(failed)) {
+		results = append(results, types.AccountEventResult{Index: uint32(idx), Result: failed[idx]})
+	}
+	return results, nil
+}
+
+func (c *fakeClient) CreateTransfers(transfers []types.Transfer) ([]types.TransferEventResult, error) {
+	c.calls++
+	failed := fakeCreate(&c.transfers, transfers, func(t types.Transfer) bool { return t.TransferFlags().Linked },
+		func(t types.Transfer) types.CreateTransferResult {
+			e, ok := c.transfer(t.ID)
+			switch {
+			case !ok:
+				return types.TransferOK
+			case t.Flags != e.Flags:
+				return types.TransferExistsWithDifferentFlags
+			case t.PendingID != e.PendingID:
+				return types.TransferExistsWithDifferentPendingID
+			case t.Timeout != e.Timeout:
+				return types.TransferExistsWithDifferentTimeout
+			case t.DebitAccountID != e.DebitAccountID:
+				return types.TransferExistsWithDifferentDebitAccountID
+			case t.CreditAccountID != e.CreditAccountID:
+				return types.TransferExistsWithDifferentCreditAccountID
+			case t.Amount != e.Amount:
+				return types.TransferExistsWithDifferentAmount
+			case t.UserData128 != e.UserData128:
+				return types.TransferExistsWithDifferentUserData128
+			case t.UserData64 != e.UserData64:
+				return types.TransferExistsWithDifferentUserData64
+			case t.UserData32 != e.UserData32:
+				return types.TransferExistsWithDifferentUserData32
+			case t.Ledger != e.Ledger:
+				return types.TransferExistsWithDifferentLedger
+			case t.Code != e.Code:
+				return types.TransferExistsWithDifferentCode
+			}
+			return types.TransferExists
+		},
+		types.TransferOK, types.TransferLinkedEventFailed, types.TransferLinkedEventChainOpen,
+	)
+	results := make([]types.TransferEventResult, 0, len(failed))
+	for _, idx := range slices.Sorted(maps.Keys(failed)) {
+		results = append(results, types.TransferEventResult{Index: uint32(idx), Result: failed[idx]})
+	}
+	return results, nil
+}
+
+func (c *fakeClient) GetAccountBalances(filter types.AccountFilter) ([]types.AccountBalance, error) {
+	return fakeAccountFilter(c.balances, func(b types.AccountBalance) uint64 { return b.Timestamp }, filter), nil
+}
+
+func (c *fakeClient) GetAccountTransfers(filter types.AccountFilter) ([]types.Transfer, error) {
+	c.pages++
+	var transfers []types.Transfer
+	for _, transfer := range c.transfers {
+		if transfer.DebitAccountID == filter.AccountID || transfer.CreditAccountID == filter.AccountID {
+			transfers = append(transfers, transfer)
+		}
+	}
+	return fakeAccountFilter(transfers, func(t types.Transfer) uint64 { return t.Timestamp }, filter), nil
+}
+
+// fakeCreate stores the events chain by chain: a chain is stored only if none of its events fails, the failed
+// event reports its result and the other events of the chain linkedFailed. Returns the failed results by index.
+func fakeCreate[T any, R comparable](store *[]T, events []T, linked func(T) bool, check func(T) R,
+	ok, linkedFailed, chainOpen R,
+) map[int]R {
+	var (
+		results = make(map[int]R)
+		start   int
+		stored  = len(*store)
+		failed  bool
+	)
+	for idx, event := range events {
+		if !failed {
+			if result := check(event); result != ok {
+				results[idx], failed = result, true
+			} else {
+				*store = append(*store, event)
+			}
+		}
+		if linked(event) && idx < len(events)-1 {
+			continue
+		}
+		if linked(event) && !failed {
+			results[idx], failed = chainOpen, true
+		}
+		if failed {
+			*store = (*store)[:stored]
+			for chained := start; chained <= idx; chained++ {
+				if _, ok := results[chained]; !ok {
+					results[chained] = linkedFailed
+				}
+			}
+		}
+		start, stored, failed = idx+1, len(*store), false
+	}
+	return results
+}
+
+// fakeAccountFilter selects the records of the filter range, order & limit.
+func fakeAccountFilter[T any](records []T, timestamp func(T) uint64, filter types.AccountFilter) []T {
+	var selected []T
+	for idx := range records {
+		record := records[idx]
+		if filter.AccountFilterFlags().Reversed {
+			record = records[len(records)-1-idx]
+		}
+		if ts := timestamp(record); ts < filter.TimestampMin || (filter.TimestampMax != 0 && ts > filter.TimestampMax) {
+			continue
+		}
+		if len(selected) == int(filter.Limit) {
+			break
+		}
+		selected = append(selected, record)
+	}
+	return selected
+}
+
+// account returns the stored account by id.
+func (c *fakeClient) account(id types.Uint128) (types.Account, bool) {
+	idx := slices.IndexFunc(c.accounts, func(a types.Account) bool { return a.ID == id })
+	if idx < 0 {
+		return types.Account{}, false
+	}
+	return c.accounts[idx], true
+}
+
+// transfer returns the stored transfer by id.
+func (c *fakeClient) transfer(id types.Uint128) (types.Transfer, bool) {
+	idx := slices.IndexFunc(c.transfers, func(t types.Transfer) bool { return t.ID == id })
+	if idx < 0 {
+		return types.Transfer{}, false
+	}
+	return c.transfers[idx], true
+}
+
+func (c *fakeClient) LookupAccounts(ids []types.Uint128) ([]types.Account, error) {
+	var found []types.Account
+	for _, id := range ids {
+		for _, account := range c.accounts {
+			if account.ID == id {
+				found = append(found, account)
+			}
+		}
+	}
+	return found, nil
+}
+
+func (c *fakeClient) QueryAccounts(filter types.QueryFilter) ([]types.Account, error) {
+	var found []types.Account
+	for _, account := range c.accounts {
+		if len(found) == int(filter.Limit) {
+			break
+		}
+		switch {
+		case filter.UserData128 != (types.Uint128{}) && filter.UserData128 != account.UserData128,
+			filter.UserData64 != 0 && filter.UserData64 != account.UserData64,
+			filter.UserData32 != 0 && filter.UserData32 != account.UserData32,
+			filter.Ledger != 0 && filter.Ledger != account.Ledger,
+			filter.Code != 0 && filter.Code != account.Code,
+			account.Timestamp < filter.TimestampMin:
+			continue
+		}
+		found = append(found, account)
+	}
+	return found, nil
+}
+
+func (c *fakeClient) LookupTransfers(ids []types.Uint128) ([]types.Transfer, error) {
+	var found []types.Transfer
+	for _, id := range ids {
+		for _, transfer := range c.transfers {
+			if transfer.ID == id {
+				found = append(found, transfer)
+			}
+		}
+	}
+	return found, nil
+}
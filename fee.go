@@ -0,0 +1,251 @@
+package tbdb
+
+import (
+	"errors"
+	"slices"
+)
+
+// FeeBearer defines which party of the base transfer bears the fee.
+type FeeBearer uint8
+
+// Enum of fee bearer.
+const (
+	// FeeBearerPayer charges the fee on top of the base amount, debited from the base debit account.
+	FeeBearerPayer FeeBearer = 1
+	// FeeBearerPayee deducts the fee from the base credit account after the base amount is credited.
+	FeeBearerPayee FeeBearer = 2
+)
+
+// FeeTier defines a fee band of a tiered fee rule.
+type FeeTier struct {
+	// UpTo is the inclusive upper bound of the base amount in minor units; zero means unbounded.
+	UpTo Uint128
+	// Percent is the percentage of the base amount, e.g. 0.7 means 0.7%.
+	Percent float64
+	// Fixed is the fixed fee in minor units.
+	Fixed Uint128
+}
+
+// FeeRule defines a single fee leg of a fee schedule.
+// The fee amount is Percent of the base amount plus Fixed, then bounded by Min and Max.
+// If Tiers is set, the first tier that covers the base amount replaces Percent and Fixed.
+type FeeRule struct {
+	// Name is the fee name shown on the breakdown, e.g. "MDR" or "Service fee".
+	Name string
+	// CreditAccountID is the account that receives the fee, usually an AccountCategoryIncome account; required.
+	CreditAccountID Uint128
+	// Percent is the percentage of the base amount, e.g. 0.7 means 0.7%.
+	Percent float64
+	// Fixed is the fixed fee in minor units.
+	Fixed Uint128
+	// Tiers is optional fee bands, evaluated in ascending UpTo order.
+	Tiers []FeeTier
+	// Min is the minimum fee in minor units; zero to disable.
+	Min Uint128
+	// Max is the fee cap in minor units; zero to disable.
+	Max Uint128
+	// Code is the transfer code of the fee leg; zero will use the base transfer code.
+	Code uint16
+	// UserData32 is 32-bit user-defined data of the fee leg.
+	UserData32 uint32
+}
+
+// FeeSchedule defines a declarative fee schedule applied on a base transfer.
+type FeeSchedule struct {
+	// Bearer is the party that bears the fee; required.
+	Bearer FeeBearer
+	// Rules is the fee legs, appended in the given order.
+	Rules []FeeRule
+	// Rounding is the rounding mode of percentage fees.
+	// Zero value RoundingUnnecessary is treated as RoundingHalfUp, since percentage fees almost always need rounding.
+	Rounding RoundingMode
+}
+
+// FeeLine defines a single computed fee of the breakdown.
+type FeeLine struct {
+	// Name is the fee rule name.
+	Name string
+	// DebitAccountID is the account that bears the fee.
+	DebitAccountID Uint128
+	// CreditAccountID is the account that receives the fee.
+	CreditAccountID Uint128
+	// Amount is the fee amount.
+	Amount Amount
+}
+
+// FeeBreakdown defines the fee breakdown of a base transfer, e.g. to be shown on receipts.
+type FeeBreakdown struct {
+	// Bearer is the party that bears the fee.
+	Bearer FeeBearer
+	// Base is the base transfer amount.
+	Base Amount
+	// Fees is each computed fee, zero fees are excluded.
+	Fees []FeeLine
+	// TotalFee is the sum of all fees.
+	TotalFee Amount
+	// PayerDebited is the total amount debited from the payer.
+	PayerDebited Amount
+	// PayeeCredited is the net amount received by the payee.
+	PayeeCredited Amount
+}
+
+// Apply computes the fees of the base transfer and returns the base transfer followed by the fee legs
+// as a linked chain, alongside the fee breakdown. Zero fees do not produce a fee leg.
+//
+// If the base transfer is already linked to the next transfer, the last fee leg keeps the chain open.
+// If the base transfer is pending, the fee legs are pending with the same timeout, so the fees are held with
+// the base amount; each fee leg is posted or voided by its own id.
+func (s FeeSchedule) Apply(base TransferData) ([]TransferData, FeeBreakdown, error) {
+	// Validate.
+	switch {
+	case base.Amount == nil:
+		return nil, FeeBreakdown{}, ErrMonetaryMustNotBeNil
+	case s.Bearer != FeeBearerPayer && s.Bearer != FeeBearerPayee:
+		return nil, FeeBreakdown{}, ErrUnknownFeeBearer
+	}
+
+	// Normalize the base amount, so arithmetic works on the Uint128 value.
+	amount := uint128Amount(base.Amount)
+	total := amount.SetUint128Value(Uint128{})
+	breakdown := FeeBreakdown{Bearer: s.Bearer, Base: amount}
+
+	// Fee legs account that bears the fee.
+	bearerAccountID := base.DebitAccountID
+	if s.Bearer == FeeBearerPayee {
+		bearerAccountID = base.CreditAccountID
+	}
+
+	chainOpen := base.flags.Linked
+	transfers := make([]TransferData, 0, len(s.Rules)+1)
+	transfers = append(transfers, base)
+	for _, rule := range s.Rules {
+		if rule.CreditAccountID.IsZero() {
+			return nil, FeeBreakdown{}, ErrAccountIDMustNotBeZero
+		}
+		fee, err := rule.compute(amount, s.Rounding)
+		if err != nil {
+			return nil, FeeBreakdown{}, err
+		}
+		if fee.IsZero() {
+			continue
+		}
+		sum, err := total.Add(fee)
+		if err != nil {
+			return nil, FeeBreakdown{}, err
+		}
+		total = total.SetUint128Value(sum)
+
+		// Fee leg.
+		code := rule.Code
+		if code == 0 {
+			code = base.code
+		}
+		transfers = append(transfers, TransferData{
+			DebitAccountID:  bearerAccountID,
+			CreditAccountID: rule.CreditAccountID,
+			Amount:          amount.SetUint128Value(fee),
+			UserData128:     base.UserData128,
+			UserData64:      base.UserData64,
+			UserData32:      rule.UserData32,
+			Ledger:          base.Ledger,
+			flags:           TransferFlags{Pending: base.flags.Pending},
+			code:            code,
+			timeout:         base.timeout,
+		})
+		breakdown.Fees = append(breakdown.Fees, FeeLine{
+			Name:            rule.Name,
+			DebitAccountID:  bearerAccountID,
+			CreditAccountID: rule.CreditAccountID,
+			Amount:          amount.SetUint128Value(fee),
+		})
+	}
+
+	// Link the chain, the last transfer closes the chain unless the base transfer keeps it open.
+	for idx := range transfers {
+		transfers[idx].flags.Linked = idx < len(transfers)-1 || chainOpen
+	}
+
+	// Breakdown totals.
+	breakdown.TotalFee = total
+	switch s.Bearer {
+	case FeeBearerPayer:
+		debited, err := amount.Add(total.ToUint128FromValue())
+		if err != nil {
+			return nil, FeeBreakdown{}, err
+		}
+		breakdown.PayerDebited = amount.SetUint128Value(debited)
+		breakdown.PayeeCredited = amount
+	case FeeBearerPayee:
+		credited, err := amount.Sub(total.ToUint128FromValue())
+		if err != nil {
+			return nil, FeeBreakdown{}, errors.Join(ErrFeeExceedsAmount, err)
+		}
+		breakdown.PayerDebited = amount
+		breakdown.PayeeCredited = amount.SetUint128Value(credited)
+	}
+	return transfers, breakdown, nil
+}
+
+// compute calculates the fee of the rule from the base amount, rounded to the minor unit with mode.
+func (r FeeRule) compute(amount Amount, mode RoundingMode) (Uint128, error) {
+	// Select the tier that covers the base amount.
+	percent, fixed := r.Percent, r.Fixed
+	if len(r.Tiers) > 0 {
+		tiers := slices.Clone(r.Tiers)
+		slices.SortStableFunc(tiers, func(a, b FeeTier) int {
+			switch {
+			case a.UpTo.IsZero() && b.UpTo.IsZero():
+				return 0
+			case a.UpTo.IsZero():
+				return 1
+			case b.UpTo.IsZero():
+				return -1
+			}
+			return a.UpTo.Compare(b.UpTo)
+		})
+		idx := slices.IndexFunc(tiers, func(t FeeTier) bool {
+			return t.UpTo.IsZero() || amount.LessThanOrEqual(t.UpTo)
+		})
+		if idx < 0 {
+			return Uint128{}, ErrFeeTierNotFound
+		}
+		percent, fixed = tiers[idx].Percent, tiers[idx].Fixed
+	}
+
+	// Exact percentage of the base amount.
+	if mode == RoundingUnnecessary {
+		mode = RoundingHalfUp
+	}
+	ratio, err := RatioFromFloat64(percent)
+	if err != nil {
+		return Uint128{}, err
+	}
+	den, overflow := mulAdd64(ratio.Den, 100, 0)
+	if overflow {
+		return Uint128{}, ErrUint128Overflow
+	}
+	percentage, err := mulDiv(amount.ToUint128FromValue(), ratio.Num, den, mode)
+	if err != nil {
+		return Uint128{}, err
+	}
+	fee, err := amount.SetUint128Value(percentage.Value).Add(fixed)
+	if err != nil {
+		return Uint128{}, err
+	}
+
+	// Bound the fee.
+	feeAmount := amount.SetUint128Value(fee)
+	if !r.Min.IsZero() && feeAmount.LessThan(r.Min) {
+		fee = r.Min
+	}
+	if !r.Max.IsZero() && feeAmount.GreaterThan(r.Max) {
+		fee = r.Max
+	}
+	return fee, nil
+}
+
+// uint128Amount returns the amount holding its value as Uint128 only, so arithmetic works on the Uint128 value.
+// A float64 value set on the amount takes precedence in ToUint128FromValue, hence it is reset first.
+func uint128Amount(a Amount) Amount {
+	return a.SetFloat64Value(0).SetUint128Value(a.ToUint128FromValue())
+}
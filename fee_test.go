@@ -0,0 +1,163 @@
+package tbdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeeScheduleApply(t *testing.T) {
+	payer, payee := Uint128FromUint64(1), Uint128FromUint64(2)
+	mdr, service := Uint128FromUint64(10), Uint128FromUint64(11)
+
+	tests := []struct {
+		name          string
+		schedule      FeeSchedule
+		amount        float64
+		wantFees      []uint64
+		wantDebited   uint64
+		wantCredited  uint64
+		wantDebitLegs Uint128
+		wantErr       error
+	}{
+		{
+			name: "payer pays percentage plus fixed",
+			schedule: FeeSchedule{Bearer: FeeBearerPayer, Rules: []FeeRule{
+				{Name: "MDR", CreditAccountID: mdr, Percent: 0.7},
+				{Name: "Service", CreditAccountID: service, Fixed: Uint128FromUint64(250_000)},
+			}},
+			amount:        100_000,
+			wantFees:      []uint64{70_000, 250_000},
+			wantDebited:   10_320_000,
+			wantCredited:  10_000_000,
+			wantDebitLegs: payer,
+		},
+		{
+			name: "payee pays with half up rounding",
+			schedule: FeeSchedule{Bearer: FeeBearerPayee, Rules: []FeeRule{
+				{Name: "MDR", CreditAccountID: mdr, Percent: 0.7},
+			}},
+			amount:        1_234.50,
+			wantFees:      []uint64{864},
+			wantDebited:   123_450,
+			wantCredited:  122_586,
+			wantDebitLegs: payee,
+		},
+		{
+			name: "payer pays with ceiling rounding",
+			schedule: FeeSchedule{Bearer: FeeBearerPayer, Rounding: RoundingCeiling, Rules: []FeeRule{
+				{Name: "MDR", CreditAccountID: mdr, Percent: 0.7},
+			}},
+			amount:        1_233,
+			wantFees:      []uint64{864},
+			wantDebited:   124_164,
+			wantCredited:  123_300,
+			wantDebitLegs: payer,
+		},
+		{
+			name: "tiered and capped",
+			schedule: FeeSchedule{Bearer: FeeBearerPayer, Rules: []FeeRule{{
+				Name:            "MDR",
+				CreditAccountID: mdr,
+				Max:             Uint128FromUint64(500_000),
+				Tiers: []FeeTier{
+					{Percent: 1},
+					{UpTo: Uint128FromUint64(1_000_000), Percent: 2},
+				},
+			}}},
+			amount:        1_000_000,
+			wantFees:      []uint64{500_000},
+			wantDebited:   100_500_000,
+			wantCredited:  100_000_000,
+			wantDebitLegs: payer,
+		},
+		{
+			name: "zero fee has no leg",
+			schedule: FeeSchedule{Bearer: FeeBearerPayer, Rules: []FeeRule{
+				{Name: "Promo", CreditAccountID: mdr},
+			}},
+			amount:       5_000,
+			wantDebited:  500_000,
+			wantCredited: 500_000,
+		},
+		{
+			name: "payee fee exceeds amount",
+			schedule: FeeSchedule{Bearer: FeeBearerPayee, Rules: []FeeRule{
+				{Name: "Service", CreditAccountID: service, Fixed: Uint128FromUint64(1_000)},
+			}},
+			amount:  5,
+			wantErr: ErrFeeExceedsAmount,
+		},
+		{
+			name:     "unknown bearer",
+			schedule: FeeSchedule{},
+			amount:   5,
+			wantErr:  ErrUnknownFeeBearer,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base := TransferData{
+				DebitAccountID:  payer,
+				CreditAccountID: payee,
+				Amount:          IDR.NewAmountFromFloat64(tt.amount),
+				Ledger:          IDR,
+			}
+			transfers, breakdown, err := tt.schedule.Apply(base)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, transfers, len(tt.wantFees)+1)
+			assert.Len(t, breakdown.Fees, len(tt.wantFees))
+			for idx, fee := range tt.wantFees {
+				leg := transfers[idx+1]
+				assert.Equal(t, Uint128FromUint64(fee), leg.Amount.ToUint128FromValue())
+				assert.Equal(t, tt.wantDebitLegs, leg.DebitAccountID)
+				assert.Equal(t, Uint128FromUint64(fee), breakdown.Fees[idx].Amount.ToUint128FromValue())
+			}
+			for idx, transfer := range transfers {
+				assert.Equal(t, idx < len(transfers)-1, transfer.flags.Linked, "linked flag of transfer %d", idx)
+			}
+			assert.Equal(t, Uint128FromUint64(tt.wantDebited), breakdown.PayerDebited.ToUint128FromValue())
+			assert.Equal(t, Uint128FromUint64(tt.wantCredited), breakdown.PayeeCredited.ToUint128FromValue())
+		})
+	}
+}
+
+func TestFeeScheduleApplyPending(t *testing.T) {
+	schedule := FeeSchedule{Bearer: FeeBearerPayer, Rules: []FeeRule{
+		{Name: "MDR", CreditAccountID: Uint128FromUint64(10), Percent: 0.7},
+		{Name: "Service", CreditAccountID: Uint128FromUint64(11), Fixed: Uint128FromUint64(250_000)},
+	}}
+	base := TransferData{
+		DebitAccountID:  Uint128FromUint64(1),
+		CreditAccountID: Uint128FromUint64(2),
+		Amount:          IDR.NewAmountFromFloat64(100_000),
+		Ledger:          IDR,
+		flags:           TransferFlags{Pending: true},
+		timeout:         3600,
+	}
+
+	transfers, _, err := schedule.Apply(base)
+	assert.NoError(t, err)
+	assert.Len(t, transfers, 3)
+	for idx, transfer := range transfers {
+		assert.True(t, transfer.flags.Pending, "pending flag of transfer %d", idx)
+		assert.Equal(t, uint32(3600), transfer.timeout, "timeout of transfer %d", idx)
+		assert.Equal(t, idx < len(transfers)-1, transfer.flags.Linked, "linked flag of transfer %d", idx)
+	}
+}
+
+func TestUint128Amount(t *testing.T) {
+	float := IDR.NewAmountFromFloat64(100)
+
+	// SetUint128Value keeps the float64 value precedence.
+	assert.Equal(t, Uint128FromUint64(10_000), float.SetUint128Value(Uint128FromUint64(5)).ToUint128FromValue())
+
+	amount := uint128Amount(float)
+	assert.Equal(t, Uint128FromUint64(10_000), amount.ToUint128FromValue())
+	assert.Equal(t, Uint128FromUint64(5), amount.SetUint128Value(Uint128FromUint64(5)).ToUint128FromValue())
+}
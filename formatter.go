@@ -0,0 +1,280 @@
+package tbdb
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Formatter defines the interface for locale-aware money formatting and parsing.
+type Formatter interface {
+	// Format formats minor units value of the currency as a human-readable string.
+	Format(cur *Currency, val Uint128, opts FormatOptions) string
+
+	// Parse parses a human-readable string into exact amount of the currency.
+	// Implementation should reject values that need more precision than the currency decimal.
+	Parse(cur *Currency, s string) (Amount, error)
+}
+
+// SymbolPosition defines where the currency symbol or code is placed.
+type SymbolPosition uint8
+
+// Enum of symbol position.
+const (
+	// SymbolPositionBefore places the symbol before the number, e.g. "Rp 20.000,50".
+	SymbolPositionBefore SymbolPosition = iota
+	// SymbolPositionAfter places the symbol after the number, e.g. "20.000 ₫".
+	SymbolPositionAfter
+)
+
+// Locale defines money formatting & parsing rules of a locale, it implements Formatter.
+type Locale struct {
+	// Name is the locale name, e.g. "id-ID".
+	Name string
+	// GroupSeparator separates the integer digit groups; empty to disable grouping.
+	GroupSeparator string
+	// DecimalSeparator separates the integer and fraction part.
+	DecimalSeparator string
+	// Grouping is the digit group sizes from the decimal separator leftwards, the last size repeats.
+	// Empty means groups of 3, Indian-style grouping is {3, 2}.
+	// A zero size ends the grouping, the remaining digits are a single group, e.g. {3, 0} for "200000,000".
+	Grouping []uint8
+	// UseSymbol renders the local currency symbol instead of the ISO code.
+	UseSymbol bool
+	// Symbols maps currency code to its local symbol, e.g. "IDR": "Rp".
+	// Currencies without symbol are rendered with the ISO code.
+	Symbols map[string]string
+	// SymbolPosition is the symbol or code placement.
+	SymbolPosition SymbolPosition
+	// SymbolSpace puts a space between the symbol and the number, ISO code is always spaced.
+	SymbolSpace bool
+}
+
+// Compile-time check if Locale implements Formatter interface.
+var _ Formatter = Locale{}
+
+// Built-in locales.
+var (
+	// LocaleEnUS formats "$20,000.50".
+	LocaleEnUS = Locale{
+		Name:             "en-US",
+		GroupSeparator:   ",",
+		DecimalSeparator: ".",
+		UseSymbol:        true,
+		Symbols:          map[string]string{"USD": "$", "EUR": "€", "SGD": "S$"},
+	}
+	// LocaleIdID formats "Rp 20.000,50".
+	LocaleIdID = Locale{
+		Name:             "id-ID",
+		GroupSeparator:   ".",
+		DecimalSeparator: ",",
+		UseSymbol:        true,
+		Symbols:          map[string]string{"IDR": "Rp", "USD": "US$"},
+		SymbolSpace:      true,
+	}
+	// LocaleViVN formats "20.000 ₫".
+	LocaleViVN = Locale{
+		Name:             "vi-VN",
+		GroupSeparator:   ".",
+		DecimalSeparator: ",",
+		UseSymbol:        true,
+		Symbols:          map[string]string{"VND": "₫", "USD": "US$"},
+		SymbolPosition:   SymbolPositionAfter,
+		SymbolSpace:      true,
+	}
+	// LocaleThTH formats "฿20,000.50".
+	LocaleThTH = Locale{
+		Name:             "th-TH",
+		GroupSeparator:   ",",
+		DecimalSeparator: ".",
+		UseSymbol:        true,
+		Symbols:          map[string]string{"THB": "฿", "USD": "US$"},
+	}
+	// LocaleEnIN formats "₹20,00,000.50" with Indian-style grouping.
+	LocaleEnIN = Locale{
+		Name:             "en-IN",
+		GroupSeparator:   ",",
+		DecimalSeparator: ".",
+		Grouping:         []uint8{3, 2},
+		UseSymbol:        true,
+		Symbols:          map[string]string{"INR": "₹", "USD": "$"},
+	}
+)
+
+// defaultLocale formats "IDR 20,000.50", used by amount Format & Uint128ToString.
+var defaultLocale = Locale{
+	GroupSeparator:   ",",
+	DecimalSeparator: ".",
+	SymbolSpace:      true,
+}
+
+// symbol returns the currency symbol or ISO code to be rendered, and whether it is spaced from the number.
+// ISO code is always spaced from the number.
+func (l Locale) symbol(cur *Currency) (string, bool) {
+	if l.UseSymbol {
+		if symbol, ok := l.Symbols[cur.code]; ok {
+			return symbol, l.SymbolSpace
+		}
+	}
+	return cur.code, true
+}
+
+// groupSize returns the size of n-th digit group from the decimal separator leftwards,
+// zero if the remaining digits are a single group.
+func (l Locale) groupSize(n int) int {
+	switch {
+	case len(l.Grouping) == 0:
+		return 3
+	case n < len(l.Grouping):
+		return int(l.Grouping[n])
+	default:
+		return int(l.Grouping[len(l.Grouping)-1])
+	}
+}
+
+// Format formats minor units value of the currency with the locale rules.
+// The value is computed directly from the 128-bit integer and the currency scale.
+func (l Locale) Format(cur *Currency, val Uint128, opts FormatOptions) string {
+	// Number with sign.
+	digits := decimalDigits(val)
+	number := make([]byte, 0, len(digits)+len(digits)/2+4)
+	switch {
+	case val.IsZero():
+	case opts.Negative:
+		number = append(number, '-')
+	case opts.PlusSign:
+		number = append(number, '+')
+	}
+	number = l.appendNumber(number, digits, cur.decimal, opts.TrimTrailingZeros)
+	if opts.HideCode {
+		return string(number)
+	}
+
+	// Place symbol.
+	symbol, space := l.symbol(cur)
+	builder := stringBuilderPool.Get().(*strings.Builder)
+	defer func() {
+		builder.Reset() // Clear before returning to pool
+		stringBuilderPool.Put(builder)
+	}()
+	builder.Grow(len(symbol) + len(number) + 1)
+	if l.SymbolPosition == SymbolPositionAfter {
+		builder.Write(number)
+		if space {
+			builder.WriteByte(' ')
+		}
+		builder.WriteString(symbol)
+		return builder.String()
+	}
+	builder.WriteString(symbol)
+	if space {
+		builder.WriteByte(' ')
+	}
+	builder.Write(number)
+	return builder.String()
+}
+
+// appendNumber appends minor units digits as decimal number scaled by 10^decimal with the locale separators.
+// If trim is true, trailing fraction zeros and a bare decimal separator are omitted.
+func (l Locale) appendNumber(dst []byte, digits string, decimal uint8, trim bool) []byte {
+	// Left pad, so there is at least one integer digit.
+	if pad := int(decimal) + 1 - len(digits); pad > 0 {
+		digits = strings.Repeat("0", pad) + digits
+	}
+	intPart, fracPart := digits[:len(digits)-int(decimal)], digits[len(digits)-int(decimal):]
+
+	// Mark group boundaries from the right, then write from the left.
+	breaks := make([]bool, len(intPart))
+	for pos, group := len(intPart), 0; l.GroupSeparator != ""; group++ {
+		size := l.groupSize(group)
+		if size == 0 {
+			break
+		}
+		pos -= size
+		if pos <= 0 {
+			break
+		}
+		breaks[pos] = true
+	}
+	for idx := range len(intPart) {
+		if breaks[idx] {
+			dst = append(dst, l.GroupSeparator...)
+		}
+		dst = append(dst, intPart[idx])
+	}
+
+	if trim {
+		fracPart = strings.TrimRight(fracPart, "0")
+	}
+	if len(fracPart) > 0 {
+		dst = append(dst, l.DecimalSeparator...)
+		dst = append(dst, fracPart...)
+	}
+	return dst
+}
+
+// Parse parses a human-readable string with the locale rules into exact amount of the currency,
+// e.g. "Rp 1.250.000" with LocaleIdID. The symbol or ISO code is optional.
+// Misplaced group separators, negative values and values that need more precision than the currency decimal are rejected.
+func (l Locale) Parse(cur *Currency, s string) (Amount, error) {
+	number, err := l.normalize(cur, s)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := cur.ParseAmount(number, RoundingUnnecessary)
+	if err != nil {
+		return nil, err
+	}
+	return amount, nil
+}
+
+// normalize strips the symbol and separators of s, returning plain decimal string accepted by parseDecimal.
+func (l Locale) normalize(cur *Currency, s string) (string, error) {
+	s = strings.TrimSpace(s)
+
+	// Strip symbol or ISO code, either side.
+	symbol, _ := l.symbol(cur)
+	for _, prefix := range []string{symbol, cur.code} {
+		if len(prefix) > 0 && len(s) >= len(prefix) && strings.EqualFold(s[:len(prefix)], prefix) {
+			s = s[len(prefix):]
+			break
+		}
+		if len(prefix) > 0 && len(s) >= len(prefix) && strings.EqualFold(s[len(s)-len(prefix):], prefix) {
+			s = s[:len(s)-len(prefix)]
+			break
+		}
+	}
+	s = strings.TrimFunc(s, unicode.IsSpace)
+	if strings.HasPrefix(s, "-") {
+		return "", ErrNegativeAmount
+	}
+	s = strings.TrimPrefix(s, "+")
+
+	// Split integer & fraction part.
+	intPart, fracPart, hasPoint := s, "", false
+	if l.DecimalSeparator != "" {
+		intPart, fracPart, hasPoint = strings.Cut(s, l.DecimalSeparator)
+	}
+
+	// Validate the digit groups, from the decimal separator leftwards.
+	if l.GroupSeparator != "" && strings.Contains(intPart, l.GroupSeparator) {
+		groups := strings.Split(intPart, l.GroupSeparator)
+		for idx, group := 0, len(groups)-1; group >= 0; idx, group = idx+1, group-1 {
+			size := l.groupSize(idx)
+			if size == 0 {
+				// Leftmost group of any size.
+				if group > 0 || len(groups[group]) == 0 {
+					return "", ErrInvalidDecimal
+				}
+				break
+			}
+			if len(groups[group]) == 0 || len(groups[group]) > size || (group > 0 && len(groups[group]) != size) {
+				return "", ErrInvalidDecimal
+			}
+		}
+		intPart = strings.Join(groups, "")
+	}
+	if hasPoint {
+		return intPart + "." + fracPart, nil
+	}
+	return intPart, nil
+}
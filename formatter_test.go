@@ -0,0 +1,83 @@
+package tbdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocaleFormat(t *testing.T) {
+	inr := newCurrency("INR", 2)
+	thousands := Locale{GroupSeparator: ",", DecimalSeparator: ".", Grouping: []uint8{3, 0}}
+
+	tests := []struct {
+		name   string
+		locale Locale
+		cur    *Currency
+		val    uint64
+		opts   FormatOptions
+		want   string
+	}{
+		{"en-US", LocaleEnUS, USD, 2_000_050, FormatOptions{}, "$20,000.50"},
+		{"en-US without symbol", LocaleEnUS, IDR, 2_000_050, FormatOptions{}, "IDR 20,000.50"},
+		{"id-ID", LocaleIdID, IDR, 2_000_050, FormatOptions{}, "Rp 20.000,50"},
+		{"id-ID trimmed", LocaleIdID, IDR, 125_000_000, FormatOptions{TrimTrailingZeros: true}, "Rp 1.250.000"},
+		{"id-ID negative", LocaleIdID, IDR, 2_000_050, FormatOptions{Negative: true}, "Rp -20.000,50"},
+		{"vi-VN", LocaleViVN, VND, 20_000, FormatOptions{}, "20.000 ₫"},
+		{"th-TH", LocaleThTH, THB, 2_000_050, FormatOptions{}, "฿20,000.50"},
+		{"en-IN", LocaleEnIN, inr, 200_000_050, FormatOptions{}, "₹20,00,000.50"},
+		{"en-IN small", LocaleEnIN, inr, 99_900, FormatOptions{}, "₹999.00"},
+		{"hide code", LocaleIdID, IDR, 2_000_050, FormatOptions{HideCode: true}, "20.000,50"},
+		{"default", defaultLocale, IDR, 2_000_050, FormatOptions{}, "IDR 20,000.50"},
+		{"zero group size", thousands, IDR, 20_000_000_050, FormatOptions{}, "IDR 200000,000.50"},
+		{"zero group size only", Locale{GroupSeparator: ",", DecimalSeparator: ".", Grouping: []uint8{0}}, IDR, 2_000_050, FormatOptions{}, "IDR 20000.50"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.locale.Format(tt.cur, Uint128FromUint64(tt.val), tt.opts)
+			assert.Equal(t, tt.want, got, "Format returned unexpected result")
+		})
+	}
+}
+
+func TestLocaleParse(t *testing.T) {
+	inr := newCurrency("INR", 2)
+	thousands := Locale{GroupSeparator: ",", DecimalSeparator: ".", Grouping: []uint8{3, 0}}
+
+	tests := []struct {
+		name    string
+		locale  Locale
+		cur     *Currency
+		input   string
+		want    uint64
+		wantErr error
+	}{
+		{"id-ID symbol", LocaleIdID, IDR, "Rp 1.250.000", 125_000_000, nil},
+		{"id-ID fraction", LocaleIdID, IDR, "Rp20.000,50", 2_000_050, nil},
+		{"id-ID iso code", LocaleIdID, IDR, "IDR 1.250.000", 125_000_000, nil},
+		{"id-ID without grouping", LocaleIdID, IDR, "1250000", 125_000_000, nil},
+		{"vi-VN symbol after", LocaleViVN, VND, "20.000 ₫", 20_000, nil},
+		{"en-US", LocaleEnUS, USD, "$1,000.05", 100_005, nil},
+		{"en-IN", LocaleEnIN, inr, "₹20,00,000.50", 200_000_050, nil},
+		{"misplaced group", LocaleIdID, IDR, "Rp 1.25", 0, ErrInvalidDecimal},
+		{"misplaced indian group", LocaleEnIN, inr, "₹2,000,000", 0, ErrInvalidDecimal},
+		{"precision", LocaleIdID, IDR, "Rp 1.000,505", 0, ErrRoundingNecessary},
+		{"negative", LocaleIdID, IDR, "Rp -1.000", 0, ErrNegativeAmount},
+		{"garbage", LocaleIdID, IDR, "Rp satu juta", 0, ErrInvalidDecimal},
+		{"zero group size", thousands, IDR, "IDR 200000,000.50", 20_000_000_050, nil},
+		{"zero group size extra group", thousands, IDR, "IDR 200,000,000.50", 0, ErrInvalidDecimal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.locale.Parse(tt.cur, tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr, "Parse(%q) must return error", tt.input)
+				return
+			}
+			assert.NoError(t, err, "Unexpected error for input %q", tt.input)
+			assert.Equal(t, Uint128FromUint64(tt.want), got.ToUint128FromValue(), "Parse(%q) returned unexpected result", tt.input)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package tbdb
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"time"
+)
+
+// IDGenerator defines the generator of account and transfer IDs that are left zero.
+type IDGenerator interface {
+	NewID() Uint128
+}
+
+// IDGeneratorFunc is a function adapter of IDGenerator.
+type IDGeneratorFunc func() Uint128
+
+// NewID implements IDGenerator.
+func (f IDGeneratorFunc) NewID() Uint128 { return f() }
+
+// DefaultIDGenerator generates TigerBeetle time-based IDs with NewID.
+var DefaultIDGenerator IDGenerator = IDGeneratorFunc(NewID)
+
+// SetIDGenerator replaces the ID generator of CreateAccountBatch and transfer creation, nil restores
+// DefaultIDGenerator. Set it before the instance is shared across goroutines.
+func (i *Instance) SetIDGenerator(gen IDGenerator) { i.idGen = gen }
+
+// newID generates an ID with the instance ID generator.
+func (i *Instance) newID() Uint128 {
+	if i.idGen == nil {
+		return DefaultIDGenerator.NewID()
+	}
+	return i.idGen.NewID()
+}
+
+// Time returns the creation time of a TigerBeetle time-based ID, the high 48 bits are milliseconds since epoch.
+// The result is meaningless for IDs that are not generated by NewID.
+func (u Uint128) Time() time.Time { return time.UnixMilli(int64(u.Hi >> 16)) }
+
+// Uint128FromUUID parses UUID string in the canonical "xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx" form,
+// or as 32 hex digits, into Uint128 with the UUID bytes as big-endian.
+func Uint128FromUUID(s string) (Uint128, error) {
+	var raw [32]byte
+	switch len(s) {
+	case 32:
+		copy(raw[:], s)
+	case 36:
+		if s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+			return Uint128{}, fmt.Errorf("%w: %q", ErrInvalidUUID, s)
+		}
+		copy(raw[0:8], s[0:8])
+		copy(raw[8:12], s[9:13])
+		copy(raw[12:16], s[14:18])
+		copy(raw[16:20], s[19:23])
+		copy(raw[20:32], s[24:36])
+	default:
+		return Uint128{}, fmt.Errorf("%w: %q", ErrInvalidUUID, s)
+	}
+	var b [16]byte
+	if _, err := hex.Decode(b[:], raw[:]); err != nil {
+		return Uint128{}, fmt.Errorf("%w: %q", ErrInvalidUUID, s)
+	}
+	return Uint128FromBytesBE(b[:])
+}
+
+// UUID returns canonical lowercase UUID string of the big-endian bytes.
+func (u Uint128) UUID() string {
+	b := u.BytesBE()
+	var dst [36]byte
+	hex.Encode(dst[0:8], b[0:4])
+	dst[8] = '-'
+	hex.Encode(dst[9:13], b[4:6])
+	dst[13] = '-'
+	hex.Encode(dst[14:18], b[6:8])
+	dst[18] = '-'
+	hex.Encode(dst[19:23], b[8:10])
+	dst[23] = '-'
+	hex.Encode(dst[24:36], b[10:16])
+	return string(dst[:])
+}
+
+// DeriveID derives a deterministic ID from a business key within a namespace, as UUID version 5
+// (RFC 9562), so the same namespace and key always give the same ID in any language.
+// Use it for idempotent creation, e.g. DeriveID(walletNamespace, "user:42:IDR").
+//
+// Derived IDs are not time-based, so Time is meaningless for them.
+func DeriveID(namespace Uint128, key string) Uint128 {
+	ns := namespace.BytesBE()
+	h := sha1.New()
+	h.Write(ns[:])
+	h.Write([]byte(key))
+	sum := h.Sum(nil)
+	sum[6] = sum[6]&0x0f | 0x50 // Version 5.
+	sum[8] = sum[8]&0x3f | 0x80 // RFC 9562 variant.
+	id, _ := Uint128FromBytesBE(sum[:16])
+	return id
+}
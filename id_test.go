@@ -0,0 +1,69 @@
+package tbdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUint128Time(t *testing.T) {
+	before := time.Now().Truncate(time.Millisecond)
+	created := NewID().Time()
+	after := time.Now()
+	assert.False(t, created.Before(before), "Time must not be before the generation")
+	assert.False(t, created.After(after), "Time must not be after the generation")
+}
+
+func TestUint128FromUUID(t *testing.T) {
+	want := Uint128{Hi: 0x6ba7b8109dad11d1, Lo: 0x80b400c04fd430c8}
+	tests := []struct {
+		name    string
+		input   string
+		want    Uint128
+		wantErr bool
+	}{
+		{"canonical", "6ba7b810-9dad-11d1-80b4-00c04fd430c8", want, false},
+		{"uppercase", "6BA7B810-9DAD-11D1-80B4-00C04FD430C8", want, false},
+		{"hex only", "6ba7b8109dad11d180b400c04fd430c8", want, false},
+		{"misplaced hyphen", "6ba7b81-09dad-11d1-80b4-00c04fd430c8", Uint128{}, true},
+		{"invalid hex", "6ba7b810-9dad-11d1-80b4-00c04fd430cz", Uint128{}, true},
+		{"short", "6ba7b810", Uint128{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Uint128FromUUID(tt.input)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidUUID, "Expected error for input %q", tt.input)
+				return
+			}
+			assert.NoError(t, err, "Unexpected error for input %q", tt.input)
+			assert.Equal(t, tt.want, got, "Uint128FromUUID(%q) returned unexpected result", tt.input)
+			assert.Equal(t, "6ba7b810-9dad-11d1-80b4-00c04fd430c8", got.UUID(), "UUID must round trip")
+		})
+	}
+}
+
+func TestDeriveID(t *testing.T) {
+	// UUID version 5 of "python.org" in the DNS namespace.
+	dns, err := Uint128FromUUID("6ba7b810-9dad-11d1-80b4-00c04fd430c8")
+	assert.NoError(t, err)
+	assert.Equal(t, "886313e1-3b8a-5372-9b90-0c9aee199e5d", DeriveID(dns, "python.org").UUID())
+
+	assert.Equal(t, DeriveID(dns, "user:42:IDR"), DeriveID(dns, "user:42:IDR"), "DeriveID must be deterministic")
+	assert.NotEqual(t, DeriveID(dns, "user:42:IDR"), DeriveID(dns, "user:42:USD"))
+	assert.NotEqual(t, DeriveID(dns, "user:42:IDR"), DeriveID(Uint128{}, "user:42:IDR"))
+}
+
+func TestInstanceIDGenerator(t *testing.T) {
+	instance := &Instance{}
+	assert.False(t, instance.newID().IsZero(), "default generator must generate non-zero ID")
+
+	fixed := Uint128FromUint64(42)
+	instance.SetIDGenerator(IDGeneratorFunc(func() Uint128 { return fixed }))
+	assert.Equal(t, fixed, instance.newID())
+
+	instance.SetIDGenerator(nil)
+	assert.NotEqual(t, fixed, instance.newID())
+}
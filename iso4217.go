@@ -0,0 +1,170 @@
+package tbdb
+
+// iso4217 is the ISO 4217 table of active currencies with official minor units.
+// Funds & precious metals without minor units, e.g. XAU or XDR, are excluded.
+var iso4217 = []CurrencyDefinition{
+	{Code: "AED", Decimal: 2, Name: "UAE Dirham", NumericCode: 784},
+	{Code: "AFN", Decimal: 2, Name: "Afghani", NumericCode: 971},
+	{Code: "ALL", Decimal: 2, Name: "Lek", NumericCode: 8},
+	{Code: "AMD", Decimal: 2, Name: "Armenian Dram", NumericCode: 51},
+	{Code: "AOA", Decimal: 2, Name: "Kwanza", NumericCode: 973},
+	{Code: "ARS", Decimal: 2, Name: "Argentine Peso", NumericCode: 32},
+	{Code: "AUD", Decimal: 2, Name: "Australian Dollar", NumericCode: 36, Symbol: "A$"},
+	{Code: "AWG", Decimal: 2, Name: "Aruban Florin", NumericCode: 533},
+	{Code: "AZN", Decimal: 2, Name: "Azerbaijan Manat", NumericCode: 944, Symbol: "₼"},
+	{Code: "BAM", Decimal: 2, Name: "Convertible Mark", NumericCode: 977},
+	{Code: "BBD", Decimal: 2, Name: "Barbados Dollar", NumericCode: 52},
+	{Code: "BDT", Decimal: 2, Name: "Taka", NumericCode: 50, Symbol: "৳"},
+	{Code: "BHD", Decimal: 3, Name: "Bahraini Dinar", NumericCode: 48},
+	{Code: "BIF", Decimal: 0, Name: "Burundi Franc", NumericCode: 108},
+	{Code: "BMD", Decimal: 2, Name: "Bermudian Dollar", NumericCode: 60},
+	{Code: "BND", Decimal: 2, Name: "Brunei Dollar", NumericCode: 96},
+	{Code: "BOB", Decimal: 2, Name: "Boliviano", NumericCode: 68},
+	{Code: "BOV", Decimal: 2, Name: "Mvdol", NumericCode: 984},
+	{Code: "BRL", Decimal: 2, Name: "Brazilian Real", NumericCode: 986, Symbol: "R$"},
+	{Code: "BSD", Decimal: 2, Name: "Bahamian Dollar", NumericCode: 44},
+	{Code: "BTN", Decimal: 2, Name: "Ngultrum", NumericCode: 64},
+	{Code: "BWP", Decimal: 2, Name: "Pula", NumericCode: 72},
+	{Code: "BYN", Decimal: 2, Name: "Belarusian Ruble", NumericCode: 933},
+	{Code: "BZD", Decimal: 2, Name: "Belize Dollar", NumericCode: 84},
+	{Code: "CAD", Decimal: 2, Name: "Canadian Dollar", NumericCode: 124, Symbol: "CA$"},
+	{Code: "CDF", Decimal: 2, Name: "Congolese Franc", NumericCode: 976},
+	{Code: "CHE", Decimal: 2, Name: "WIR Euro", NumericCode: 947},
+	{Code: "CHF", Decimal: 2, Name: "Swiss Franc", NumericCode: 756},
+	{Code: "CHW", Decimal: 2, Name: "WIR Franc", NumericCode: 948},
+	{Code: "CLF", Decimal: 4, Name: "Unidad de Fomento", NumericCode: 990},
+	{Code: "CLP", Decimal: 0, Name: "Chilean Peso", NumericCode: 152},
+	{Code: "CNY", Decimal: 2, Name: "Yuan Renminbi", NumericCode: 156, Symbol: "¥"},
+	{Code: "COP", Decimal: 2, Name: "Colombian Peso", NumericCode: 170},
+	{Code: "COU", Decimal: 2, Name: "Unidad de Valor Real", NumericCode: 970},
+	{Code: "CRC", Decimal: 2, Name: "Costa Rican Colon", NumericCode: 188, Symbol: "₡"},
+	{Code: "CUP", Decimal: 2, Name: "Cuban Peso", NumericCode: 192},
+	{Code: "CVE", Decimal: 2, Name: "Cabo Verde Escudo", NumericCode: 132},
+	{Code: "CZK", Decimal: 2, Name: "Czech Koruna", NumericCode: 203, Symbol: "Kč"},
+	{Code: "DJF", Decimal: 0, Name: "Djibouti Franc", NumericCode: 262},
+	{Code: "DKK", Decimal: 2, Name: "Danish Krone", NumericCode: 208, Symbol: "kr"},
+	{Code: "DOP", Decimal: 2, Name: "Dominican Peso", NumericCode: 214},
+	{Code: "DZD", Decimal: 2, Name: "Algerian Dinar", NumericCode: 12},
+	{Code: "EGP", Decimal: 2, Name: "Egyptian Pound", NumericCode: 818},
+	{Code: "ERN", Decimal: 2, Name: "Nakfa", NumericCode: 232},
+	{Code: "ETB", Decimal: 2, Name: "Ethiopian Birr", NumericCode: 230},
+	{Code: "EUR", Decimal: 2, Name: "Euro", NumericCode: 978, Symbol: "€"},
+	{Code: "FJD", Decimal: 2, Name: "Fiji Dollar", NumericCode: 242},
+	{Code: "FKP", Decimal: 2, Name: "Falkland Islands Pound", NumericCode: 238},
+	{Code: "GBP", Decimal: 2, Name: "Pound Sterling", NumericCode: 826, Symbol: "£"},
+	{Code: "GEL", Decimal: 2, Name: "Lari", NumericCode: 981, Symbol: "₾"},
+	{Code: "GHS", Decimal: 2, Name: "Ghana Cedi", NumericCode: 936, Symbol: "₵"},
+	{Code: "GIP", Decimal: 2, Name: "Gibraltar Pound", NumericCode: 292},
+	{Code: "GMD", Decimal: 2, Name: "Dalasi", NumericCode: 270},
+	{Code: "GNF", Decimal: 0, Name: "Guinean Franc", NumericCode: 324},
+	{Code: "GTQ", Decimal: 2, Name: "Quetzal", NumericCode: 320},
+	{Code: "GYD", Decimal: 2, Name: "Guyana Dollar", NumericCode: 328},
+	{Code: "HKD", Decimal: 2, Name: "Hong Kong Dollar", NumericCode: 344, Symbol: "HK$"},
+	{Code: "HNL", Decimal: 2, Name: "Lempira", NumericCode: 340},
+	{Code: "HTG", Decimal: 2, Name: "Gourde", NumericCode: 332},
+	{Code: "HUF", Decimal: 2, Name: "Forint", NumericCode: 348, Symbol: "Ft"},
+	{Code: "IDR", Decimal: 2, Name: "Rupiah", NumericCode: 360, Symbol: "Rp"},
+	{Code: "ILS", Decimal: 2, Name: "New Israeli Sheqel", NumericCode: 376, Symbol: "₪"},
+	{Code: "INR", Decimal: 2, Name: "Indian Rupee", NumericCode: 356, Symbol: "₹"},
+	{Code: "IQD", Decimal: 3, Name: "Iraqi Dinar", NumericCode: 368},
+	{Code: "IRR", Decimal: 2, Name: "Iranian Rial", NumericCode: 364},
+	{Code: "ISK", Decimal: 0, Name: "Iceland Krona", NumericCode: 352},
+	{Code: "JMD", Decimal: 2, Name: "Jamaican Dollar", NumericCode: 388},
+	{Code: "JOD", Decimal: 3, Name: "Jordanian Dinar", NumericCode: 400},
+	{Code: "JPY", Decimal: 0, Name: "Yen", NumericCode: 392, Symbol: "¥"},
+	{Code: "KES", Decimal: 2, Name: "Kenyan Shilling", NumericCode: 404},
+	{Code: "KGS", Decimal: 2, Name: "Som", NumericCode: 417},
+	{Code: "KHR", Decimal: 2, Name: "Riel", NumericCode: 116, Symbol: "៛"},
+	{Code: "KMF", Decimal: 0, Name: "Comorian Franc", NumericCode: 174},
+	{Code: "KPW", Decimal: 2, Name: "North Korean Won", NumericCode: 408},
+	{Code: "KRW", Decimal: 0, Name: "Won", NumericCode: 410, Symbol: "₩"},
+	{Code: "KWD", Decimal: 3, Name: "Kuwaiti Dinar", NumericCode: 414},
+	{Code: "KYD", Decimal: 2, Name: "Cayman Islands Dollar", NumericCode: 136},
+	{Code: "KZT", Decimal: 2, Name: "Tenge", NumericCode: 398, Symbol: "₸"},
+	{Code: "LAK", Decimal: 2, Name: "Lao Kip", NumericCode: 418, Symbol: "₭"},
+	{Code: "LBP", Decimal: 2, Name: "Lebanese Pound", NumericCode: 422},
+	{Code: "LKR", Decimal: 2, Name: "Sri Lanka Rupee", NumericCode: 144},
+	{Code: "LRD", Decimal: 2, Name: "Liberian Dollar", NumericCode: 430},
+	{Code: "LSL", Decimal: 2, Name: "Loti", NumericCode: 426},
+	{Code: "LYD", Decimal: 3, Name: "Libyan Dinar", NumericCode: 434},
+	{Code: "MAD", Decimal: 2, Name: "Moroccan Dirham", NumericCode: 504},
+	{Code: "MDL", Decimal: 2, Name: "Moldovan Leu", NumericCode: 498},
+	{Code: "MGA", Decimal: 2, Name: "Malagasy Ariary", NumericCode: 969},
+	{Code: "MKD", Decimal: 2, Name: "Denar", NumericCode: 807},
+	{Code: "MMK", Decimal: 2, Name: "Kyat", NumericCode: 104},
+	{Code: "MNT", Decimal: 2, Name: "Tugrik", NumericCode: 496, Symbol: "₮"},
+	{Code: "MOP", Decimal: 2, Name: "Pataca", NumericCode: 446},
+	{Code: "MRU", Decimal: 2, Name: "Ouguiya", NumericCode: 929},
+	{Code: "MUR", Decimal: 2, Name: "Mauritius Rupee", NumericCode: 480},
+	{Code: "MVR", Decimal: 2, Name: "Rufiyaa", NumericCode: 462},
+	{Code: "MWK", Decimal: 2, Name: "Malawi Kwacha", NumericCode: 454},
+	{Code: "MXN", Decimal: 2, Name: "Mexican Peso", NumericCode: 484, Symbol: "MX$"},
+	{Code: "MXV", Decimal: 2, Name: "Mexican Unidad de Inversion (UDI)", NumericCode: 979},
+	{Code: "MYR", Decimal: 2, Name: "Malaysian Ringgit", NumericCode: 458, Symbol: "RM"},
+	{Code: "MZN", Decimal: 2, Name: "Mozambique Metical", NumericCode: 943},
+	{Code: "NAD", Decimal: 2, Name: "Namibia Dollar", NumericCode: 516},
+	{Code: "NGN", Decimal: 2, Name: "Naira", NumericCode: 566, Symbol: "₦"},
+	{Code: "NIO", Decimal: 2, Name: "Cordoba Oro", NumericCode: 558},
+	{Code: "NOK", Decimal: 2, Name: "Norwegian Krone", NumericCode: 578, Symbol: "kr"},
+	{Code: "NPR", Decimal: 2, Name: "Nepalese Rupee", NumericCode: 524},
+	{Code: "NZD", Decimal: 2, Name: "New Zealand Dollar", NumericCode: 554, Symbol: "NZ$"},
+	{Code: "OMR", Decimal: 3, Name: "Rial Omani", NumericCode: 512},
+	{Code: "PAB", Decimal: 2, Name: "Balboa", NumericCode: 590},
+	{Code: "PEN", Decimal: 2, Name: "Sol", NumericCode: 604},
+	{Code: "PGK", Decimal: 2, Name: "Kina", NumericCode: 598},
+	{Code: "PHP", Decimal: 2, Name: "Philippine Peso", NumericCode: 608, Symbol: "₱"},
+	{Code: "PKR", Decimal: 2, Name: "Pakistan Rupee", NumericCode: 586},
+	{Code: "PLN", Decimal: 2, Name: "Zloty", NumericCode: 985, Symbol: "zł"},
+	{Code: "PYG", Decimal: 0, Name: "Guarani", NumericCode: 600, Symbol: "₲"},
+	{Code: "QAR", Decimal: 2, Name: "Qatari Rial", NumericCode: 634},
+	{Code: "RON", Decimal: 2, Name: "Romanian Leu", NumericCode: 946},
+	{Code: "RSD", Decimal: 2, Name: "Serbian Dinar", NumericCode: 941},
+	{Code: "RUB", Decimal: 2, Name: "Russian Ruble", NumericCode: 643, Symbol: "₽"},
+	{Code: "RWF", Decimal: 0, Name: "Rwanda Franc", NumericCode: 646},
+	{Code: "SAR", Decimal: 2, Name: "Saudi Riyal", NumericCode: 682},
+	{Code: "SBD", Decimal: 2, Name: "Solomon Islands Dollar", NumericCode: 90},
+	{Code: "SCR", Decimal: 2, Name: "Seychelles Rupee", NumericCode: 690},
+	{Code: "SDG", Decimal: 2, Name: "Sudanese Pound", NumericCode: 938},
+	{Code: "SEK", Decimal: 2, Name: "Swedish Krona", NumericCode: 752, Symbol: "kr"},
+	{Code: "SGD", Decimal: 2, Name: "Singapore Dollar", NumericCode: 702, Symbol: "S$"},
+	{Code: "SHP", Decimal: 2, Name: "Saint Helena Pound", NumericCode: 654},
+	{Code: "SLE", Decimal: 2, Name: "Leone", NumericCode: 925},
+	{Code: "SOS", Decimal: 2, Name: "Somali Shilling", NumericCode: 706},
+	{Code: "SRD", Decimal: 2, Name: "Surinam Dollar", NumericCode: 968},
+	{Code: "SSP", Decimal: 2, Name: "South Sudanese Pound", NumericCode: 728},
+	{Code: "STN", Decimal: 2, Name: "Dobra", NumericCode: 930},
+	{Code: "SVC", Decimal: 2, Name: "El Salvador Colon", NumericCode: 222},
+	{Code: "SYP", Decimal: 2, Name: "Syrian Pound", NumericCode: 760},
+	{Code: "SZL", Decimal: 2, Name: "Lilangeni", NumericCode: 748},
+	{Code: "THB", Decimal: 2, Name: "Baht", NumericCode: 764, Symbol: "฿"},
+	{Code: "TJS", Decimal: 2, Name: "Somoni", NumericCode: 972},
+	{Code: "TMT", Decimal: 2, Name: "Turkmenistan New Manat", NumericCode: 934},
+	{Code: "TND", Decimal: 3, Name: "Tunisian Dinar", NumericCode: 788},
+	{Code: "TOP", Decimal: 2, Name: "Pa'anga", NumericCode: 776},
+	{Code: "TRY", Decimal: 2, Name: "Turkish Lira", NumericCode: 949, Symbol: "₺"},
+	{Code: "TTD", Decimal: 2, Name: "Trinidad and Tobago Dollar", NumericCode: 780},
+	{Code: "TWD", Decimal: 2, Name: "New Taiwan Dollar", NumericCode: 901, Symbol: "NT$"},
+	{Code: "TZS", Decimal: 2, Name: "Tanzanian Shilling", NumericCode: 834},
+	{Code: "UAH", Decimal: 2, Name: "Hryvnia", NumericCode: 980, Symbol: "₴"},
+	{Code: "UGX", Decimal: 0, Name: "Uganda Shilling", NumericCode: 800},
+	{Code: "USD", Decimal: 2, Name: "US Dollar", NumericCode: 840, Symbol: "$"},
+	{Code: "USN", Decimal: 2, Name: "US Dollar (Next day)", NumericCode: 997},
+	{Code: "UYI", Decimal: 0, Name: "Uruguay Peso en Unidades Indexadas (UI)", NumericCode: 940},
+	{Code: "UYU", Decimal: 2, Name: "Peso Uruguayo", NumericCode: 858},
+	{Code: "UYW", Decimal: 4, Name: "Unidad Previsional", NumericCode: 927},
+	{Code: "UZS", Decimal: 2, Name: "Uzbekistan Sum", NumericCode: 860},
+	{Code: "VED", Decimal: 2, Name: "Bolívar Soberano", NumericCode: 926},
+	{Code: "VES", Decimal: 2, Name: "Bolívar Soberano", NumericCode: 928},
+	{Code: "VND", Decimal: 0, Name: "Dong", NumericCode: 704, Symbol: "₫"},
+	{Code: "VUV", Decimal: 0, Name: "Vatu", NumericCode: 548},
+	{Code: "WST", Decimal: 2, Name: "Tala", NumericCode: 882},
+	{Code: "XAF", Decimal: 0, Name: "CFA Franc BEAC", NumericCode: 950},
+	{Code: "XCD", Decimal: 2, Name: "East Caribbean Dollar", NumericCode: 951, Symbol: "EC$"},
+	{Code: "XCG", Decimal: 2, Name: "Caribbean Guilder", NumericCode: 532},
+	{Code: "XOF", Decimal: 0, Name: "CFA Franc BCEAO", NumericCode: 952},
+	{Code: "XPF", Decimal: 0, Name: "CFP Franc", NumericCode: 953},
+	{Code: "YER", Decimal: 2, Name: "Yemeni Rial", NumericCode: 886},
+	{Code: "ZAR", Decimal: 2, Name: "Rand", NumericCode: 710, Symbol: "R"},
+	{Code: "ZMW", Decimal: 2, Name: "Zambian Kwacha", NumericCode: 967},
+	{Code: "ZWG", Decimal: 2, Name: "Zimbabwe Gold", NumericCode: 924},
+}
@@ -6,15 +6,31 @@ import (
 	"log"
 	"math"
 	"math/big"
-	"strconv"
 	"strings"
 	"sync"
 )
 
-// currency represents a currency definition with its code and decimal precision.
-type currency struct {
-	code    string
-	decimal uint8
+// Currency represents a currency definition with its code, decimal precision and metadata.
+type Currency struct {
+	code        string
+	decimal     uint8
+	name        string
+	symbol      string
+	numericCode uint16
+}
+
+// CurrencyDefinition defines a currency, e.g. an ISO 4217 entry or a custom token from config.
+type CurrencyDefinition struct {
+	// Code is the currency code, 1-6 characters, e.g. "IDR" or "DOGE"; required.
+	Code string `json:"code" mapstructure:"code"`
+	// Decimal is the decimal precision (minor units), 0-38 so 10^Decimal fits in Uint128.
+	Decimal uint8 `json:"decimal" mapstructure:"decimal"`
+	// Name is the currency name, e.g. "Rupiah".
+	Name string `json:"name" mapstructure:"name"`
+	// Symbol is the currency symbol, e.g. "Rp".
+	Symbol string `json:"symbol" mapstructure:"symbol"`
+	// NumericCode is the ISO 4217 numeric code, zero for non ISO currencies.
+	NumericCode uint16 `json:"numeric_code" mapstructure:"numeric_code"`
 }
 
 // === Begin Ledger Implement ===
@@ -22,60 +38,69 @@ type currency struct {
 const (
 	minCurrencyCodeChar = 1
 	maxCurrencyCodeChar = 6
-	maxDecimal          = 99
+	maxDecimal          = 38 // 10^38 is the largest power of ten that fits in Uint128.
 )
 
-// Compile-time check if *currency implements Ledger interface.
-var _ Ledger = (*currency)(nil)
+// Compile-time check if *Currency implements Ledger interface.
+var _ Ledger = (*Currency)(nil)
 
 // Built-in currencies with their decimal precision.
+// Every other ISO 4217 currency is available with LookupCurrency.
 var (
 	// Vietnamese Dong (no decimals).
-	VND *currency = newCurrency("VND", 0)
+	VND *Currency = isoCurrency("VND")
 	// Indonesian Rupiah.
-	IDR *currency = newCurrency("IDR", 2)
+	IDR *Currency = isoCurrency("IDR")
 	// Malaysian Ringgit.
-	MYR *currency = newCurrency("MYR", 2)
+	MYR *Currency = isoCurrency("MYR")
 	// Singapore Dollar.
-	SGD *currency = newCurrency("SGD", 2)
+	SGD *Currency = isoCurrency("SGD")
 	// Thai Baht.
-	THB *currency = newCurrency("THB", 2)
+	THB *Currency = isoCurrency("THB")
 	// Philippine Peso.
-	PHP *currency = newCurrency("PHP", 2)
+	PHP *Currency = isoCurrency("PHP")
 	// US Dollar.
-	USD *currency = newCurrency("USD", 2)
+	USD *Currency = isoCurrency("USD")
 	// Euro.
-	EUR *currency = newCurrency("EUR", 2)
+	EUR *Currency = isoCurrency("EUR")
+
+	// Crypto currencies have no ISO 4217 numeric code, so they can't be a TenantLedger currency.
 
 	// Tether (6 decimals).
-	USDT *currency = newCurrency("USDT", 6)
+	USDT *Currency = newCurrencyFromDefinition(CurrencyDefinition{Code: "USDT", Decimal: 6, Name: "Tether", Symbol: "₮"})
 	// Bitcoin (8 decimals).
-	BTC *currency = newCurrency("BTC", 8)
+	BTC *Currency = newCurrencyFromDefinition(CurrencyDefinition{Code: "BTC", Decimal: 8, Name: "Bitcoin", Symbol: "₿"})
 	// Binance Coin (12 decimals).
-	BNB *currency = newCurrency("BNB", 12)
+	BNB *Currency = newCurrencyFromDefinition(CurrencyDefinition{Code: "BNB", Decimal: 12, Name: "Binance Coin"})
 	// Ethereum (18 decimals).
-	ETH *currency = newCurrency("ETH", 18)
+	ETH *Currency = newCurrencyFromDefinition(CurrencyDefinition{Code: "ETH", Decimal: 18, Name: "Ethereum", Symbol: "Ξ"})
 )
 
-// hashCurrencyCode creates a deterministic hash for currency codes > 3 characters.
-// Uses FNV-1a algorithm to ensure consistent results.
-func hashCurrencyCode(code string) uint32 {
-	// FNV-1a offset basis.
-	hash := uint32(2166136261)
-	for _, c := range code {
-		hash ^= uint32(c)
-		// FNV-1a prime.
-		hash *= 16777619
+// newCurrency creates a new currency without validation.
+// Used internally for built-in currencies.
+func newCurrency(code string, decimal uint8) *Currency {
+	return newCurrencyFromDefinition(CurrencyDefinition{Code: code, Decimal: decimal})
+}
+
+// newCurrencyFromDefinition creates a new currency from definition without validation.
+func newCurrencyFromDefinition(def CurrencyDefinition) *Currency {
+	return &Currency{
+		code:        strings.ToUpper(strings.TrimSpace(def.Code)),
+		decimal:     def.Decimal,
+		name:        def.Name,
+		symbol:      def.Symbol,
+		numericCode: def.NumericCode,
 	}
-	// Constrain to 6 digits (0-999999)
-	return hash % 1000000
 }
 
-// newCurrency creates a new currency without validation.
-// Used internally for built-in currencies.
-func newCurrency(code string, decimal uint8) *currency {
-	code = strings.ToUpper(strings.TrimSpace(code))
-	return &currency{code: code, decimal: decimal}
+// isoCurrency creates the currency of the ISO 4217 table, it panics on unknown code.
+func isoCurrency(code string) *Currency {
+	for _, def := range iso4217 {
+		if def.Code == code {
+			return newCurrencyFromDefinition(def)
+		}
+	}
+	panic("tbdb: unknown ISO 4217 currency " + code)
 }
 
 // NewCurrency creates a new currency with validation.
@@ -85,112 +110,84 @@ func newCurrency(code string, decimal uint8) *currency {
 //	if err != nil {
 //		log.Println(err)
 //	}
-func NewCurrency(code string, decimal uint8) (*currency, error) {
-	// Validate decimal range (0-99).
-	if decimal > maxDecimal {
-		return nil, fmt.Errorf("supported decimal is 0-%d", maxDecimal)
+func NewCurrency(code string, decimal uint8) (*Currency, error) {
+	return NewCurrencyFromDefinition(CurrencyDefinition{Code: code, Decimal: decimal})
+}
+
+// NewCurrencyFromDefinition creates a new currency with metadata and validation.
+// Returns error if code length or decimal precision is invalid.
+//
+//	doge, err := tbdb.NewCurrencyFromDefinition(tbdb.CurrencyDefinition{Code: "DOGE", Decimal: 8, Name: "Dogecoin", Symbol: "Ð"})
+func NewCurrencyFromDefinition(def CurrencyDefinition) (*Currency, error) {
+	// Validate decimal range (0-38).
+	if err := validateDecimal(def.Decimal); err != nil {
+		return nil, fmt.Errorf("currency %s: %w", strings.TrimSpace(def.Code), err)
 	}
 	// Validate code length (1-6 characters).
-	codelen := len(strings.TrimSpace(code))
+	codelen := len(strings.TrimSpace(def.Code))
 	if codelen < minCurrencyCodeChar || codelen > maxCurrencyCodeChar {
 		return nil, fmt.Errorf("supported currency char length is %d-%d", minCurrencyCodeChar, maxCurrencyCodeChar)
 	}
-	return newCurrency(code, decimal), nil
+	return newCurrencyFromDefinition(def), nil
 }
 
-// EncodeLedger generates a unique LedgerCode for TigerBeetle.
-// Format: [3-digit decimal + 100][6-digit currency encoding]
-//
-// Encoding strategy:
-//   - Codes ≤3 chars: Direct base-100 encoding (reversible)
-//   - Codes >3 chars: FNV hash + offset (deterministic but not reversible)
-func (c *currency) EncodeLedger() LedgerCode {
-	// Decimal part: 100-199 (represents 0-99 decimal precision).
-	decimalPart := uint32(c.decimal) + 100
-
-	var codeVal uint32
-	if len(c.code) <= 3 {
-		// Direct encoding for short codes (≤3 chars).
-		// Each character encoded as 2 digits in base-100.
-		// Result range: 0-353535 (for direct encoding).
-		for i := range 3 {
-			var n uint32
-			if i < len(c.code) {
-				char := c.code[i]
-				switch {
-				case char >= 'A' && char <= 'Z':
-					n = uint32(char-'A') + 10 // A=10, B=11, ..., Z=35
-				case char >= '0' && char <= '9':
-					n = uint32(char - '0') // 0=0, 1=1, ..., 9=9
-				default:
-					n = 0 // Invalid chars treated as padding
-				}
-			} else {
-				n = 0 // Padding for shorter codes
-			}
-			codeVal = codeVal*100 + n // Base-100 untuk ensure fit
-		}
-	} else {
-		// Hash encoding for long codes (>3 chars).
-		// Result range: 500000-999999 (hash + offset to avoid collision).
-		hash := hashCurrencyCode(c.code)
-		codeVal = 500000 + (hash % 500000)
+// validateDecimal returns ErrUnsupportedDecimal if 10^decimal does not fit in Uint128.
+func validateDecimal(decimal uint8) error {
+	if decimal > maxDecimal {
+		return fmt.Errorf("%w: %d, supported is 0-%d", ErrUnsupportedDecimal, decimal, maxDecimal)
 	}
+	return nil
+}
+
+// Code returns the currency code, e.g. "IDR".
+func (c *Currency) Code() string { return c.code }
+
+// Decimal returns the decimal precision (minor units).
+func (c *Currency) Decimal() uint8 { return c.decimal }
+
+// Name returns the currency name, e.g. "Rupiah"; empty if not defined.
+func (c *Currency) Name() string { return c.name }
+
+// Symbol returns the currency symbol, e.g. "Rp"; empty if not defined.
+func (c *Currency) Symbol() string { return c.symbol }
+
+// NumericCode returns the ISO 4217 numeric code, e.g. 360 for IDR; zero for non ISO currencies.
+func (c *Currency) NumericCode() uint16 { return c.numericCode }
 
-	// Combine: [3 digits][6 digits] = 9 digits total.
-	return LedgerCode(decimalPart*1000000 + codeVal)
+// Definition returns the currency definition.
+func (c *Currency) Definition() CurrencyDefinition {
+	return CurrencyDefinition{
+		Code:        c.code,
+		Decimal:     c.decimal,
+		Name:        c.name,
+		Symbol:      c.symbol,
+		NumericCode: c.numericCode,
+	}
+}
+
+// EncodeLedger generates a unique LedgerCode for TigerBeetle with the package LedgerEncoder,
+// HashLedgerEncoder by default. Returns zero, which TigerBeetle rejects, if the encoder cannot encode the currency.
+func (c *Currency) EncodeLedger() LedgerCode {
+	ledger, err := CurrentLedgerEncoder().EncodeLedger(c.code, c.decimal)
+	if err != nil {
+		log.Printf("[tbdb] Warning: encode ledger of %s: %s", c.code, err.Error())
+		return 0
+	}
+	return ledger
 }
 
 // DecodeLedger converts the LedgerCode back to human-readable format.
 // Returns: "decimal=<precision>, currency=<code>"
 //
 // Decoding behavior:
-//   - Direct encoded (value < 500000): Reverses the encoding to get original code
-//   - Hash encoded (value ≥ 500000): Uses stored currency code (not reversible)
-func (c *currency) DecodeLedger() string {
-	ledgerCode := c.EncodeLedger()
-
-	// Extract decimal and currency parts.
-	decimal := int(ledgerCode/1000000) - 100
-	codeValue := uint32(ledgerCode % 1000000)
-
-	var currencyCode string
-	if codeValue < 500000 {
-		// Direct encoded: reverse the base-100 encoding.
-		temp := codeValue
-		chars := make([]uint32, 3)
-		// Extract each character value (reverse order).
-		for i := 2; i >= 0; i-- {
-			chars[i] = temp % 100
-			temp = temp / 100
-		}
-
-		// Convert character values back to letters/digits.
-		var builder strings.Builder
-		for _, charValue := range chars {
-			if charValue == 0 {
-				continue // Skip padding zeros
-			}
-
-			var ch byte
-			switch {
-			case charValue >= 1 && charValue <= 9:
-				ch = byte('0' + charValue) // Numbers: 1-9 → '1'-'9'
-			case charValue >= 10 && charValue <= 35:
-				ch = byte('A' + charValue - 10) // Letters: 10-35 → 'A'-'Z'
-			}
-			if ch != 0 {
-				builder.WriteByte(ch)
-			}
-		}
-		currencyCode = builder.String()
-	} else {
-		// Hash encoded: use original currency code.
-		// (Hash is not reversible, so we return the stored value).
-		currencyCode = c.code
+//   - Reversible ledger: Decodes the ledger with the package LedgerEncoder
+//   - Otherwise, e.g. hash encoded: Uses stored currency code & decimal
+func (c *Currency) DecodeLedger() string {
+	code, decimal, err := CurrentLedgerEncoder().DecodeLedger(c.EncodeLedger())
+	if err != nil {
+		code, decimal = c.code, c.decimal
 	}
-
-	return fmt.Sprintf("decimal=%d, currency=%s", decimal, currencyCode)
+	return fmt.Sprintf("decimal=%d, currency=%s", decimal, code)
 }
 
 // === End Ledger Implement ===
@@ -198,73 +195,56 @@ func (c *currency) DecodeLedger() string {
 // === Begin Amount Implement ===
 
 type amountCurrency struct {
-	curr       *currency
+	curr       *Currency
 	float64Val float64
 	uint128Val Uint128
 }
 
 // NewMonetary creates new currency monetary.
-func (c *currency) NewMonetary() *amountCurrency {
+func (c *Currency) NewMonetary() *amountCurrency {
 	return &amountCurrency{
 		curr: c,
 	}
 }
 
 // NewAmountFromFloat64 creates new currency amount from float64 value.
-func (c *currency) NewAmountFromFloat64(val float64) *amountCurrency {
+func (c *Currency) NewAmountFromFloat64(val float64) *amountCurrency {
 	return &amountCurrency{
 		curr:       c,
 		float64Val: val,
 	}
 }
 
-// Compile-time check to ensure *currency implements Amount interface.
-var _ Amount = (*amountCurrency)(nil)
+// NewAmountFromMinorUnits creates new currency amount from minor units value, e.g. cents for USD.
+func (c *Currency) NewAmountFromMinorUnits(val Uint128) *amountCurrency {
+	return &amountCurrency{
+		curr:       c,
+		uint128Val: val,
+	}
+}
 
-// Pre-computed scale values for common decimal precisions (0-19).
-// Provides O(1) lookup performance for frequently used decimal places.
-var scaleCache = [31]uint64{
-	1,                    // 0 decimals
-	10,                   // 1 decimal
-	100,                  // 2 decimals
-	1000,                 // 3 decimals
-	10000,                // 4 decimals
-	100000,               // 5 decimals
-	1000000,              // 6 decimals
-	10000000,             // 7 decimals
-	100000000,            // 8 decimals
-	1000000000,           // 9 decimals
-	10000000000,          // 10 decimals
-	100000000000,         // 11 decimals
-	1000000000000,        // 12 decimals
-	10000000000000,       // 13 decimals
-	100000000000000,      // 14 decimals
-	1000000000000000,     // 15 decimals
-	10000000000000000,    // 16 decimals
-	100000000000000000,   // 17 decimals
-	1000000000000000000,  // 18 decimals
-	10000000000000000000, // 19 decimals
-	// Extended for edge cases
-	1000000000000000000, // 20 decimals (will overflow, but we handle it)
-	1000000000000000000, // 21+ decimals (fallback to safe value)
-	1000000000000000000, // ...continuing with safe fallback
-	1000000000000000000,
-	1000000000000000000,
-	1000000000000000000,
-	1000000000000000000,
-	1000000000000000000,
-	1000000000000000000,
-	1000000000000000000,
-	1000000000000000000,
-}
-
-// Pool for reusing big.Float instances
-var bigFloatPool = sync.Pool{
-	New: func() any {
-		return new(big.Float)
-	},
+// NewAmountFromString creates new currency amount from exact decimal string without going through float64.
+// Returns ErrRoundingNecessary if the value needs more precision than the currency decimal.
+//
+//	amount, err := tbdb.ETH.NewAmountFromString("12345.678901234567890123")
+func (c *Currency) NewAmountFromString(val string) (*amountCurrency, error) {
+	return c.ParseAmount(val, RoundingUnnecessary)
+}
+
+// ParseAmount parses exact decimal string, e.g. "20000.50", into currency amount.
+// Fraction digits beyond the currency decimal are rounded with the given mode,
+// RoundingUnnecessary rejects them instead.
+func (c *Currency) ParseAmount(val string, mode RoundingMode) (*amountCurrency, error) {
+	minorUnits, err := parseDecimal(val, c.decimal, mode)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s amount %q: %w", c.code, val, err)
+	}
+	return c.NewAmountFromMinorUnits(minorUnits), nil
 }
 
+// Compile-time check to ensure *Currency implements Amount interface.
+var _ Amount = (*amountCurrency)(nil)
+
 // Pool for big.Int operations
 var bigIntPool = sync.Pool{
 	New: func() any {
@@ -280,94 +260,12 @@ var stringBuilderPool = sync.Pool{
 }
 
 // scaleFromDecimals returns the scaling factor (10^decimals) for the given decimal precision.
-// Uses pre-computed cache for common values (0-19) for optimal performance.
-func scaleFromDecimals(decimals uint8) uint64 {
-	if decimals < 31 {
-		return scaleCache[decimals] // O(1) lookup, extended range
-	}
-
-	// Fallback for extreme cases (>30 decimals) - return safe value
-	log.Printf("[tbdb] Warning: decimal precision %d exceeds cache, using fallback", decimals)
-	return 1000000000000000000 // 10^18 as reasonable max
-}
-
-// formatWithCommas adds thousand separators to an integer value.
-// Optimized for performance with minimal memory allocations.
-func formatWithCommas(value int64) string {
-	// Convert to string
-	str := strconv.FormatInt(value, 10)
-
-	// No formatting needed for numbers with 3 digits or less.
-	if len(str) <= 3 {
-		return str
-	}
-
-	// Calculate exact size needed for result
-	commaCount := (len(str) - 1) / 3
-	resultSize := len(str) + commaCount
-
-	// Single allocation with exact capacity
-	result := make([]byte, 0, resultSize)
-
-	// Build result with commas in single pass
-	for i, digit := range str {
-		if i > 0 && (len(str)-i)%3 == 0 {
-			result = append(result, ',')
-		}
-		result = append(result, byte(digit))
-	}
-	return string(result)
-}
-
-// formatCurrency formats a float64 value as a currency string with thousand separators.
-// Handles decimal precision according to the specified decimal places.
-func formatCurrency(value float64, decimal uint8) string {
-	// Handle special floating-point values.
-	if math.IsInf(value, 0) || math.IsNaN(value) {
-		return "0"
-	}
-
-	// Separate integer and fractional parts.
-	intPart := int64(value)
-	fracPart := value - float64(intPart)
-
-	// Format integer part with thousand separators.
-	intStr := formatWithCommas(intPart)
-
-	// Return integer-only format if no decimal places required.
-	if decimal == 0 {
-		return intStr
-	}
-
-	// Calculate and format fractional part with proper rounding.
-	scale := scaleFromDecimals(decimal)
-	fracScaled := int64(math.Round(fracPart * float64(scale)))
-
-	// Handle overflow in fractional part
-	if fracScaled >= int64(scale) {
-		intPart++
-		fracScaled = 0
-		intStr = formatWithCommas(intPart) // Recalculate if carry-over
+// Uses pre-computed powers of ten, false if the scale does not fit in Uint128.
+func scaleFromDecimals(decimals uint8) (Uint128, bool) {
+	if decimals > maxDecimal {
+		return Uint128{}, false
 	}
-
-	// Pre-calculate result size to minimize allocations
-	resultSize := len(intStr) + 1 + int(decimal) // intStr + "." + decimals
-
-	// Build result with single allocation
-	result := make([]byte, 0, resultSize)
-	result = append(result, intStr...)
-	result = append(result, '.')
-
-	// Format fractional part with leading zeros
-	fracStr := strconv.FormatInt(fracScaled, 10)
-
-	// Add leading zeros if needed
-	for i := len(fracStr); i < int(decimal); i++ {
-		result = append(result, '0')
-	}
-
-	result = append(result, fracStr...)
-	return string(result)
+	return pow10Uint128[decimals], true
 }
 
 func (a *amountCurrency) clone() *amountCurrency {
@@ -406,18 +304,13 @@ func (a *amountCurrency) Float64ToUint128() (Uint128, error) {
 	}
 
 	// Apply scaling with rounding to handle floating-point precision issues.
-	scale := scaleFromDecimals(a.curr.decimal)
-	scaledVal := val * float64(scale)
-	roundedVal := math.Round(scaledVal)
-
-	// Check for values exceeding uint64 maximum.
-	if roundedVal > float64(math.MaxUint64) {
-		return Uint128{}, fmt.Errorf("value %f too large for Uint128", val)
+	// math.Pow10 is exact up to 10^22 and correctly rounded beyond.
+	roundedVal := math.Round(val * math.Pow10(int(a.curr.decimal)))
+	intVal, ok := uint128FromFloat64(roundedVal)
+	if !ok {
+		return Uint128{}, fmt.Errorf("value %g too large for Uint128: %w", val, ErrUint128Overflow)
 	}
-
-	// Convert to uint64 dan then to Uint128
-	intVal := uint64(roundedVal)
-	return Uint128FromUint64(intVal), nil
+	return intVal, nil
 }
 
 // ToUint128FromValue returns Uint128 valu type depends on value that has been set.
@@ -441,18 +334,15 @@ func (a *amountCurrency) ToUint128FromValue() Uint128 {
 // Example: For IDR (2 decimals), 2000050 becomes 20000.50.
 func (a *amountCurrency) Uint128ToFloat64() float64 {
 	val := a.uint128Val
-	bigInt := val.BigInt()
 
 	// Fast path 1: Zero value
-	if bigInt.Sign() == 0 {
+	if val.IsZero() {
 		return 0.0
 	}
 
-	scale := scaleFromDecimals(a.curr.decimal)
-
 	// Fast path 2: Small values that fit in uint64 (most common case)
-	if bigInt.IsUint64() {
-		intVal := bigInt.Uint64()
+	if val.IsUint64() {
+		intVal := val.Lo
 
 		// Ultra-fast path for no decimals
 		if a.curr.decimal == 0 {
@@ -472,28 +362,47 @@ func (a *amountCurrency) Uint128ToFloat64() float64 {
 		case 18:
 			return float64(intVal) / 1000000000000000000.0
 		default:
-			return float64(intVal) / float64(scale)
+			return float64(intVal) / math.Pow10(int(a.curr.decimal))
 		}
 	}
 
-	// Slow path: Use pooled big.Float for large values
-	floatVal := bigFloatPool.Get().(*big.Float)
-	scaleFloat := bigFloatPool.Get().(*big.Float)
-	result := bigFloatPool.Get().(*big.Float)
-	defer func() {
-		floatVal.SetInt64(0) // Reset before returning to pool
-		scaleFloat.SetInt64(0)
-		result.SetInt64(0)
-		bigFloatPool.Put(floatVal)
-		bigFloatPool.Put(scaleFloat)
-		bigFloatPool.Put(result)
-	}()
+	// Unsupported precision, scale in float64.
+	scale, ok := scaleFromDecimals(a.curr.decimal)
+	if !ok {
+		return val.Float64() / math.Pow10(int(a.curr.decimal))
+	}
+
+	// Large values: split into integer and fraction parts, so the fraction keeps its precision.
+	intPart, fracPart := val.QuoRem(scale)
+	return intPart.Float64() + fracPart.Float64()/scale.Float64()
+}
+
+// FormatOptions defines amount string formatting options.
+type FormatOptions struct {
+	// TrimTrailingZeros omits trailing fraction zeros, and the decimal point if the fraction is zero.
+	TrimTrailingZeros bool
+	// HideCode omits the currency code.
+	HideCode bool
+	// Negative renders a non-zero amount with '-' sign, e.g. for statement debit columns.
+	Negative bool
+	// PlusSign renders a non-zero and non-negative amount with '+' sign, e.g. for statement credit columns.
+	PlusSign bool
+}
+
+// Format formats the Uint128 amount with the given options.
+// The value is computed directly from the 128-bit integer and the currency scale, so it is exact
+// for every representable amount.
+//
+// Example: For IDR, 2000050 with Negative option becomes "IDR -20,000.50".
+func (a *amountCurrency) Format(opts FormatOptions) string {
+	return defaultLocale.Format(a.curr, a.uint128Val, opts)
+}
 
-	floatVal.SetInt(bigInt)
-	scaleFloat.SetUint64(scale)
-	result.Quo(floatVal, scaleFloat)
-	humanValue, _ := result.Float64()
-	return humanValue
+// FormatWith formats the Uint128 amount with the given locale-aware formatter.
+//
+// Example: For IDR, 2000050 with LocaleIdID becomes "Rp 20.000,50".
+func (a *amountCurrency) FormatWith(f Formatter, opts FormatOptions) string {
+	return f.Format(a.curr, a.uint128Val, opts)
 }
 
 // Uint128ToString formats a Uint128 amount as a complete currency string.
@@ -501,208 +410,121 @@ func (a *amountCurrency) Uint128ToFloat64() float64 {
 //
 // Example: For IDR, 2000050 becomes "IDR 20,000.50".
 func (a *amountCurrency) Uint128ToString() string {
-	// Get pooled string builder
-	builder := stringBuilderPool.Get().(*strings.Builder)
-	defer func() {
-		builder.Reset() // Clear before returning to pool
-		stringBuilderPool.Put(builder)
-	}()
-
-	// Fast path for zero
-	val := a.uint128Val
-	bigInt := val.BigInt()
-	if bigInt.Sign() == 0 {
-		builder.Grow(len(a.curr.code) + 10) // Pre-allocate for "CODE 0.00"
-		builder.WriteString(a.curr.code)
-		builder.WriteByte(' ')
-		builder.WriteByte('0')
-
-		if a.curr.decimal > 0 {
-			builder.WriteByte('.')
-			for i := uint8(0); i < a.curr.decimal; i++ {
-				builder.WriteByte('0')
-			}
-		}
-
-		return builder.String()
-	}
-
-	// Get human value using optimized conversion.
-	humanValue := a.Uint128ToFloat64()
-
-	// Pre-allocate with estimated size.
-	estimatedSize := len(a.curr.code) + 25 // Conservative estimate
-	builder.Grow(estimatedSize)
-
-	// Build result
-	builder.WriteString(a.curr.code)
-	builder.WriteByte(' ')
-	builder.WriteString(formatCurrency(humanValue, a.curr.decimal))
-	return builder.String()
+	return a.Format(FormatOptions{})
 }
 
 // Add safely adds two Uint128 amounts with overflow protection.
-// Uses fast path for small values, falls back to big.Int for large values.
 func (a *amountCurrency) Add(b Uint128) (Uint128, error) {
-	x := a.uint128Val
-	aBigInt := x.BigInt()
-	bBigInt := b.BigInt()
-
-	// Fast path: both values fit in uint64 and no overflow risk.
-	if aBigInt.IsUint64() && bBigInt.IsUint64() {
-		aVal := aBigInt.Uint64()
-		bVal := bBigInt.Uint64()
-		if aVal <= math.MaxUint64-bVal {
-			return Uint128FromUint64(aVal + bVal), nil
-		}
+	sum, overflow := a.uint128Val.Add(b)
+	if overflow {
+		return Uint128{}, ErrUint128Overflow
 	}
-
-	// Slow path: with pooled big.Int.
-	result := bigIntPool.Get().(*big.Int)
-	defer bigIntPool.Put(result)
-	result.Add(aBigInt, bBigInt)
-
-	// Direct overflow check without string allocation
-	if result.BitLen() > 128 {
-		return Uint128{}, errors.New("addition overflow")
-	}
-	return Uint128FromBigInt(result)
+	return sum, nil
 }
 
 // Sub safely subtracts b from a with underflow protection.
 // Returns error if the result would be negative.
 func (a *amountCurrency) Sub(b Uint128) (Uint128, error) {
-	x := a.uint128Val
-	aBigInt := x.BigInt()
-	bBigInt := b.BigInt()
-
-	// Fast path: both values fit in uint64.
-	if aBigInt.IsUint64() && bBigInt.IsUint64() {
-		aVal := aBigInt.Uint64()
-		bVal := bBigInt.Uint64()
-		if aVal < bVal {
-			return Uint128{}, ErrNegativeOrNilBigInt
-		}
-		return Uint128FromUint64(aVal - bVal), nil
-	}
-
-	// Slow path: use big.Int arithmetic.
-	if aBigInt.Cmp(bBigInt) < 0 {
+	diff, underflow := a.uint128Val.Sub(b)
+	if underflow {
 		return Uint128{}, ErrNegativeOrNilBigInt
 	}
-	result := new(big.Int).Sub(aBigInt, bBigInt)
-	return Uint128FromBigInt(result)
+	return diff, nil
 }
 
-// Mul multiplies an amount by a floating-point multiplier.
-// Commonly used for calculating fees or applying exchange rates.
+// Mul multiplies an amount by a floating-point multiplier, truncating the result.
+// The multiplier is taken as its shortest decimal representation, so there is no binary float error.
+// Commonly used for calculating fees or applying exchange rates, use MulRatio for explicit rounding.
 func (a *amountCurrency) Mul(multiplier float64) (Uint128, error) {
 	if multiplier < 0 {
 		multiplier = 0
 	}
-	amount := a.uint128Val
-	amountBigInt := amount.BigInt()
-
-	// Get pooled big.Float instances
-	multiplierBig := bigFloatPool.Get().(*big.Float)
-	amountFloat := bigFloatPool.Get().(*big.Float)
-	result := bigFloatPool.Get().(*big.Float)
-	defer func() {
-		multiplierBig.SetFloat64(0)
-		amountFloat.SetInt64(0)
-		result.SetFloat64(0)
-		bigFloatPool.Put(multiplierBig)
-		bigFloatPool.Put(amountFloat)
-		bigFloatPool.Put(result)
-	}()
-
-	multiplierBig.SetFloat64(multiplier)
-	amountFloat.SetInt(amountBigInt)
-	result.Mul(amountFloat, multiplierBig)
-	resultInt, _ := result.Int(nil)
-
-	// Optimized overflow check
-	if resultInt.BitLen() > 128 {
-		return Uint128{}, errors.New("multiplication overflow")
+	ratio, err := RatioFromFloat64(multiplier)
+	if err != nil {
+		return Uint128{}, err
 	}
-	return Uint128FromBigInt(resultInt)
+	return a.MulRatio(ratio, RoundingTruncate)
 }
 
-// Div divides an amount by a floating-point divisor.
+// Div divides an amount by a floating-point divisor, truncating the result.
 // Returns error for invalid divisors (zero, infinity, NaN).
 func (a *amountCurrency) Div(divisor float64) (Uint128, error) {
 	if divisor == 0 || math.IsInf(divisor, 0) || math.IsNaN(divisor) {
 		return Uint128{}, errors.New("invalid divisor")
 	}
-	amount := a.uint128Val
-	amountBigInt := amount.BigInt()
-
-	// Get pooled big.Float instances
-	divisorBig := bigFloatPool.Get().(*big.Float)
-	amountFloat := bigFloatPool.Get().(*big.Float)
-	result := bigFloatPool.Get().(*big.Float)
-	defer func() {
-		divisorBig.SetFloat64(0)
-		amountFloat.SetInt64(0)
-		result.SetFloat64(0)
-		bigFloatPool.Put(divisorBig)
-		bigFloatPool.Put(amountFloat)
-		bigFloatPool.Put(result)
-	}()
-
-	divisorBig.SetFloat64(divisor)
-	amountFloat.SetInt(amountBigInt)
-	result.Quo(amountFloat, divisorBig)
-	resultInt, _ := result.Int(nil)
-
-	// Ensure result is non-negative.
-	if resultInt.Sign() < 0 {
+	if divisor < 0 {
 		return Uint128{}, ErrNegativeOrNilBigInt
 	}
-	return Uint128FromBigInt(resultInt)
+	ratio, err := RatioFromFloat64(divisor)
+	if err != nil {
+		return Uint128{}, err
+	}
+	return a.DivRatio(ratio, RoundingTruncate)
 }
 
-// Percentage calculates a percentage of the given amount.
+// Percentage calculates a percentage of the given amount, truncating the result.
 //
 // Example: Percentage(1000, 0.7) returns 0.7% of 1000 = 7.
 func (a *amountCurrency) Percentage(percent float64) (Uint128, error) {
-	return a.Mul(percent / 100.0)
+	if percent < 0 {
+		percent = 0
+	}
+	ratio, err := RatioFromFloat64(percent)
+	if err != nil {
+		return Uint128{}, err
+	}
+	return a.PercentageRatio(ratio, RoundingTruncate)
+}
+
+// MulDiv multiplies an amount by num/den exactly and rounds with the given mode.
+// The result carries the remainder, so fee or interest calculations can be audited.
+//
+// Example: For 1000, MulDiv(7, 1000, RoundingHalfEven) returns Value 7, Remainder 0.
+func (a *amountCurrency) MulDiv(num, den Uint128, mode RoundingMode) (RoundResult, error) {
+	return mulDiv(a.uint128Val, num, den, mode)
+}
+
+// MulRatio multiplies an amount by an exact rational factor and rounds with the given mode.
+func (a *amountCurrency) MulRatio(r Ratio, mode RoundingMode) (Uint128, error) {
+	result, err := a.MulDiv(r.Num, r.Den, mode)
+	if err != nil {
+		return Uint128{}, err
+	}
+	return result.Value, nil
+}
+
+// DivRatio divides an amount by an exact rational divisor and rounds with the given mode.
+// Returns ErrDivisionByZero if the divisor is zero.
+func (a *amountCurrency) DivRatio(r Ratio, mode RoundingMode) (Uint128, error) {
+	if r.IsZero() {
+		return Uint128{}, ErrDivisionByZero
+	}
+	return a.MulRatio(r.Inverse(), mode)
+}
+
+// PercentageRatio calculates an exact rational percentage of an amount and rounds with the given mode.
+//
+// Example: For 123450, PercentageRatio(NewRatio(7, 10), RoundingHalfUp) returns 0.7% of 123450 = 864.
+func (a *amountCurrency) PercentageRatio(percent Ratio, mode RoundingMode) (Uint128, error) {
+	den, overflow := mulAdd64(percent.Den, 100, 0)
+	if overflow {
+		return Uint128{}, ErrUint128Overflow
+	}
+	return a.MulRatio(Ratio{Num: percent.Num, Den: den}, mode)
 }
 
 // Compare compares two Uint128 amounts.
-// Uses fast path for values that fit in uint64.
 // Returns:
 //   - -1 if a < b
 //   - 0 if a == b
 //   - 1 if a > b.
 func (a *amountCurrency) Compare(b Uint128) int {
-	x := a.uint128Val
-	aBigInt := x.BigInt()
-	bBigInt := b.BigInt()
-
-	// Fast path: both values fit in uint64.
-	if aBigInt.IsUint64() && bBigInt.IsUint64() {
-		aVal := aBigInt.Uint64()
-		bVal := bBigInt.Uint64()
-		switch {
-		case aVal < bVal:
-			return -1
-		case aVal > bVal:
-			return 1
-		default:
-			return 0
-		}
-	}
-
-	// Slow path: full big.Int comparison.
-	return aBigInt.Cmp(bBigInt)
+	return a.uint128Val.Compare(b)
 }
 
-// IsZero checks if the Uint128 value represents zero or negative (treated as zero).
+// IsZero checks if the Uint128 value represents zero.
 func (a *amountCurrency) IsZero() bool {
-	val := a.uint128Val
-	bigInt := val.BigInt()
-	return bigInt.Sign() <= 0
+	return a.uint128Val.IsZero()
 }
 
 // Equal checks if two Uint128 amounts are equal.
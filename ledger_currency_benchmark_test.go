@@ -30,7 +30,7 @@ package tbdb
 // // === Ledger Encoding/Decoding Benchmarks ===
 
 // func BenchmarkEncodeLedger(b *testing.B) {
-// 	currencies := []*currency{USD, IDR, BTC, ETH, VND, USDT}
+// 	currencies := []*Currency{USD, IDR, BTC, ETH, VND, USDT}
 // 	for i := 0; b.Loop(); i++ {
 // 		curr := currencies[i%len(currencies)]
 // 		_ = curr.EncodeLedger()
@@ -38,7 +38,7 @@ package tbdb
 // }
 
 // func BenchmarkDecodeLedger(b *testing.B) {
-// 	currencies := []*currency{USD, IDR, BTC, ETH, VND, USDT}
+// 	currencies := []*Currency{USD, IDR, BTC, ETH, VND, USDT}
 // 	for i := 0; b.Loop(); i++ {
 // 		curr := currencies[i%len(currencies)]
 // 		_ = curr.DecodeLedger()
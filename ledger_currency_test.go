@@ -99,7 +99,7 @@ package tbdb
 
 // func TestBuiltInCurrencies(t *testing.T) {
 // 	tests := []struct {
-// 		currency *currency
+// 		currency *Currency
 // 		code     string
 // 		decimal  uint8
 // 	}{
@@ -126,7 +126,7 @@ package tbdb
 // func TestEncodeLedger(t *testing.T) {
 // 	tests := []struct {
 // 		name     string
-// 		currency *currency
+// 		currency *Currency
 // 		validate func(t *testing.T, code LedgerCode)
 // 	}{
 // 		{
@@ -178,7 +178,7 @@ package tbdb
 // }
 
 // func TestDecodeLedger(t *testing.T) {
-// 	currencies := []*currency{USD, IDR, BTC, ETH, VND, USDT}
+// 	currencies := []*Currency{USD, IDR, BTC, ETH, VND, USDT}
 
 // 	for _, curr := range currencies {
 // 		t.Run(curr.code, func(t *testing.T) {
@@ -200,7 +200,7 @@ package tbdb
 // func TestFloat64ToUint128(t *testing.T) {
 // 	tests := []struct {
 // 		name     string
-// 		currency *currency
+// 		currency *Currency
 // 		input    float64
 // 		expected uint64 // Expected as uint64 for simple verification
 // 		hasError bool
@@ -265,7 +265,7 @@ package tbdb
 // func TestUint128ToFloat64(t *testing.T) {
 // 	tests := []struct {
 // 		name     string
-// 		currency *currency
+// 		currency *Currency
 // 		input    uint64
 // 		expected float64
 // 	}{
@@ -307,7 +307,7 @@ package tbdb
 // func TestUint128ToString(t *testing.T) {
 // 	tests := []struct {
 // 		name     string
-// 		currency *currency
+// 		currency *Currency
 // 		input    uint64
 // 		expected string
 // 	}{
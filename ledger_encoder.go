@@ -0,0 +1,359 @@
+package tbdb
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// LedgerEncoder defines the encoding of currency code & decimal into TigerBeetle ledger.
+type LedgerEncoder interface {
+	// EncodeLedger encodes the currency code & decimal into ledger.
+	EncodeLedger(code string, decimal uint8) (LedgerCode, error)
+
+	// DecodeLedger decodes the ledger back into currency code & decimal.
+	// Returns ErrUnknownLedgerCode if the ledger is not reversible.
+	DecodeLedger(ledger LedgerCode) (code string, decimal uint8, err error)
+}
+
+// Compile-time check if encoders implement LedgerEncoder interface.
+var (
+	_ LedgerEncoder = HashLedgerEncoder{}
+	_ LedgerEncoder = Base36LedgerEncoder{}
+	_ LedgerEncoder = (*LedgerAllocator)(nil)
+)
+
+// ledgerEncoder holds the package LedgerEncoder.
+var ledgerEncoder atomic.Pointer[LedgerEncoder]
+
+// CurrentLedgerEncoder returns the package LedgerEncoder used by Currency.EncodeLedger.
+func CurrentLedgerEncoder() LedgerEncoder {
+	if enc := ledgerEncoder.Load(); enc != nil {
+		return *enc
+	}
+	return HashLedgerEncoder{}
+}
+
+// SetLedgerEncoder replaces the package LedgerEncoder, nil restores HashLedgerEncoder.
+// Registered currencies are re-encoded, so it returns ErrLedgerCodeCollision and keeps the current encoder
+// if two of them would share a ledger. Set it once at startup, before any account is created,
+// and use PlanLedgerMigration to review the ledgers that would change.
+func SetLedgerEncoder(enc LedgerEncoder) error {
+	if enc == nil {
+		enc = HashLedgerEncoder{}
+	}
+	currencies.mu.Lock()
+	defer currencies.mu.Unlock()
+	byLedger := make(map[LedgerCode]*Currency, len(currencies.byCode))
+	for _, cur := range currencies.byCode {
+		ledger, err := enc.EncodeLedger(cur.code, cur.decimal)
+		if err != nil {
+			return fmt.Errorf("encode ledger of %s: %w", cur.code, err)
+		}
+		if existing, ok := byLedger[ledger]; ok {
+			return fmt.Errorf("%w: %s and %s share ledger %d", ErrLedgerCodeCollision, cur.code, existing.code, ledger)
+		}
+		byLedger[ledger] = cur
+	}
+	currencies.byLedger = byLedger
+	ledgerEncoder.Store(&enc)
+	return nil
+}
+
+// HashLedgerEncoder is the default ledger encoding.
+// Format: [3-digit decimal + 100][6-digit currency encoding]
+//
+// Encoding strategy:
+//   - Codes ≤3 chars: Direct base-100 encoding (reversible)
+//   - Codes >3 chars: FNV hash + offset (deterministic but not reversible)
+//
+// Unsupported characters are treated as padding and long codes may share a hash,
+// register currencies with a LedgerAllocator to detect such collisions.
+type HashLedgerEncoder struct{}
+
+// EncodeLedger implements LedgerEncoder.
+func (HashLedgerEncoder) EncodeLedger(code string, decimal uint8) (LedgerCode, error) {
+	// Decimal part: 100-138 (represents 0-38 decimal precision).
+	if err := validateDecimal(decimal); err != nil {
+		return 0, err
+	}
+	decimalPart := uint32(decimal) + 100
+
+	var codeVal uint32
+	if len(code) <= 3 {
+		// Direct encoding for short codes (≤3 chars).
+		// Each character encoded as 2 digits in base-100.
+		// Result range: 0-353535 (for direct encoding).
+		for i := range 3 {
+			var n uint32
+			if i < len(code) {
+				char := code[i]
+				switch {
+				case char >= 'A' && char <= 'Z':
+					n = uint32(char-'A') + 10 // A=10, B=11, ..., Z=35
+				case char >= '0' && char <= '9':
+					n = uint32(char - '0') // 0=0, 1=1, ..., 9=9
+				default:
+					n = 0 // Invalid chars treated as padding
+				}
+			} else {
+				n = 0 // Padding for shorter codes
+			}
+			codeVal = codeVal*100 + n // Base-100 untuk ensure fit
+		}
+	} else {
+		// Hash encoding for long codes (>3 chars).
+		// Result range: 500000-999999 (hash + offset to avoid collision).
+		hash := hashCurrencyCode(code)
+		codeVal = 500000 + (hash % 500000)
+	}
+
+	// Combine: [3 digits][6 digits] = 9 digits total.
+	return LedgerCode(decimalPart*1000000 + codeVal), nil
+}
+
+// DecodeLedger implements LedgerEncoder, only direct encoded codes (≤3 chars) are reversible.
+func (HashLedgerEncoder) DecodeLedger(ledger LedgerCode) (string, uint8, error) {
+	decimalPart, codeValue := uint32(ledger)/1000000, uint32(ledger)%1000000
+	if decimalPart < 100 || decimalPart > 100+maxDecimal || codeValue >= 500000 || codeValue == 0 {
+		return "", 0, fmt.Errorf("%w: %d", ErrUnknownLedgerCode, ledger)
+	}
+
+	// Reverse the base-100 encoding, skip padding zeros.
+	var builder strings.Builder
+	for div := uint32(10000); div > 0; div /= 100 {
+		switch charValue := codeValue / div % 100; {
+		case charValue == 0:
+		case charValue <= 9:
+			builder.WriteByte(byte('0' + charValue))
+		case charValue <= 35:
+			builder.WriteByte(byte('A' + charValue - 10))
+		default:
+			return "", 0, fmt.Errorf("%w: %d", ErrUnknownLedgerCode, ledger)
+		}
+	}
+	return builder.String(), uint8(decimalPart - 100), nil
+}
+
+// hashCurrencyCode creates a deterministic hash for currency codes > 3 characters.
+// Uses FNV-1a algorithm to ensure consistent results.
+func hashCurrencyCode(code string) uint32 {
+	// FNV-1a offset basis.
+	hash := uint32(2166136261)
+	for _, c := range code {
+		hash ^= uint32(c)
+		// FNV-1a prime.
+		hash *= 16777619
+	}
+	// Constrain to 6 digits (0-999999)
+	return hash % 1000000
+}
+
+// Base36LedgerEncoder is a reversible ledger encoding of codes up to 6 characters of A-Z & 0-9,
+// as bijective base-36 number in 1-base36MaxLedger, so every code has its own ledger.
+// The decimal is not part of the ledger, it is decoded from the currency registry,
+// so one code can only have one decimal precision.
+type Base36LedgerEncoder struct{}
+
+// base36MaxLedger is the ledger of "ZZZZZZ", the largest Base36LedgerEncoder ledger.
+const base36MaxLedger LedgerCode = 2_238_976_116
+
+// EncodeLedger implements LedgerEncoder.
+func (Base36LedgerEncoder) EncodeLedger(code string, _ uint8) (LedgerCode, error) {
+	if len(code) < minCurrencyCodeChar || len(code) > maxCurrencyCodeChar {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidCurrencyCode, code)
+	}
+	var ledger uint32
+	for idx := 0; idx < len(code); idx++ {
+		var digit uint32
+		switch char := code[idx]; {
+		case char >= '0' && char <= '9':
+			digit = uint32(char-'0') + 1 // 0=1, ..., 9=10
+		case char >= 'A' && char <= 'Z':
+			digit = uint32(char-'A') + 11 // A=11, ..., Z=36
+		default:
+			return 0, fmt.Errorf("%w: %q", ErrInvalidCurrencyCode, code)
+		}
+		ledger = ledger*36 + digit
+	}
+	return LedgerCode(ledger), nil
+}
+
+// DecodeLedger implements LedgerEncoder, the decimal comes from the registered currency of the code.
+func (Base36LedgerEncoder) DecodeLedger(ledger LedgerCode) (string, uint8, error) {
+	var (
+		buf [maxCurrencyCodeChar]byte
+		pos = len(buf)
+	)
+	for val := uint32(ledger); val > 0; val = (val - 1) / 36 {
+		if pos == 0 {
+			return "", 0, fmt.Errorf("%w: %d", ErrUnknownLedgerCode, ledger)
+		}
+		pos--
+		switch digit := (val-1)%36 + 1; {
+		case digit <= 10:
+			buf[pos] = byte('0' + digit - 1)
+		default:
+			buf[pos] = byte('A' + digit - 11)
+		}
+	}
+	code := string(buf[pos:])
+	if code == "" {
+		return "", 0, fmt.Errorf("%w: %d", ErrUnknownLedgerCode, ledger)
+	}
+	cur, ok := LookupCurrency(code)
+	if !ok {
+		return "", 0, fmt.Errorf("%w: %d decodes into unregistered %s", ErrUnknownLedgerCode, ledger, code)
+	}
+	return code, cur.decimal, nil
+}
+
+// LedgerAllocator is a registry-backed LedgerEncoder on top of a base encoder.
+// Each currency is registered on its first encoding, and registration fails with ErrLedgerCodeCollision
+// if the ledger is already allocated to another currency, instead of silently sharing it.
+// Ledgers can be pinned with Assign, e.g. to keep existing ledgers while resolving a collision.
+type LedgerAllocator struct {
+	mu       sync.RWMutex
+	base     LedgerEncoder
+	byLedger map[LedgerCode]allocatedLedger
+	byCode   map[string]LedgerCode
+}
+
+// allocatedLedger is the currency code & decimal of an allocated ledger.
+type allocatedLedger struct {
+	code    string
+	decimal uint8
+}
+
+// NewLedgerAllocator creates a registry-backed allocator, nil base uses HashLedgerEncoder.
+func NewLedgerAllocator(base LedgerEncoder) *LedgerAllocator {
+	if base == nil {
+		base = HashLedgerEncoder{}
+	}
+	return &LedgerAllocator{
+		base:     base,
+		byLedger: make(map[LedgerCode]allocatedLedger),
+		byCode:   make(map[string]LedgerCode),
+	}
+}
+
+// Register allocates the ledger of the currency with the base encoder.
+func (a *LedgerAllocator) Register(code string, decimal uint8) (LedgerCode, error) {
+	ledger, err := a.base.EncodeLedger(code, decimal)
+	if err != nil {
+		return 0, err
+	}
+	return ledger, a.Assign(code, decimal, ledger)
+}
+
+// Assign pins the ledger of the currency, assigning the same currency & ledger again is a no-op.
+func (a *LedgerAllocator) Assign(code string, decimal uint8, ledger LedgerCode) error {
+	if ledger == 0 {
+		return fmt.Errorf("%w: %d", ErrUnknownLedgerCode, ledger)
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	want := allocatedLedger{code: code, decimal: decimal}
+	if existing, ok := a.byLedger[ledger]; ok {
+		if existing != want {
+			return fmt.Errorf("%w: %s and %s share ledger %d", ErrLedgerCodeCollision, code, existing.code, ledger)
+		}
+		return nil
+	}
+	if existing, ok := a.byCode[code]; ok {
+		return fmt.Errorf("%w: %s is allocated to ledger %d", ErrCurrencyCodeRegistered, code, existing)
+	}
+	a.byLedger[ledger] = want
+	a.byCode[code] = ledger
+	return nil
+}
+
+// EncodeLedger implements LedgerEncoder, unallocated currencies are registered.
+func (a *LedgerAllocator) EncodeLedger(code string, decimal uint8) (LedgerCode, error) {
+	a.mu.RLock()
+	ledger, ok := a.byCode[code]
+	allocated := a.byLedger[ledger]
+	a.mu.RUnlock()
+	if ok {
+		if allocated.decimal != decimal {
+			return 0, fmt.Errorf("%w: %s with %d decimals", ErrCurrencyCodeRegistered, code, allocated.decimal)
+		}
+		return ledger, nil
+	}
+	return a.Register(code, decimal)
+}
+
+// clone returns a copy of the allocator, so encoding with the copy does not register on the original.
+func (a *LedgerAllocator) clone() *LedgerAllocator {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return &LedgerAllocator{
+		base:     sideEffectFree(a.base),
+		byLedger: maps.Clone(a.byLedger),
+		byCode:   maps.Clone(a.byCode),
+	}
+}
+
+// sideEffectFree returns a copy of the encoder if encoding changes it, i.e. registers on a LedgerAllocator.
+func sideEffectFree(encoder LedgerEncoder) LedgerEncoder {
+	if allocator, ok := encoder.(*LedgerAllocator); ok {
+		return allocator.clone()
+	}
+	return encoder
+}
+
+// DecodeLedger implements LedgerEncoder from the allocated ledgers.
+func (a *LedgerAllocator) DecodeLedger(ledger LedgerCode) (string, uint8, error) {
+	a.mu.RLock()
+	allocated, ok := a.byLedger[ledger]
+	a.mu.RUnlock()
+	if !ok {
+		return "", 0, fmt.Errorf("%w: %d", ErrUnknownLedgerCode, ledger)
+	}
+	return allocated.code, allocated.decimal, nil
+}
+
+// LedgerMigration defines the ledger change of a currency between two encoders.
+type LedgerMigration struct {
+	// Currency is the migrated currency.
+	Currency *Currency
+	// From is the ledger with the current encoder.
+	From LedgerCode
+	// To is the ledger with the target encoder.
+	To LedgerCode
+	// Err is the encoding error or ErrLedgerCodeCollision on the target encoder.
+	Err error
+}
+
+// Changed returns true if the currency moves into another ledger.
+func (m LedgerMigration) Changed() bool { return m.Err == nil && m.From != m.To }
+
+// PlanLedgerMigration reports the ledger of each currency with both encoders, without changing anything.
+// Without currencies, every registered currency is planned, sorted by code.
+// Accounts of a changed currency stay on the old ledger, so they need to be migrated, e.g. by closing & reopening.
+// A LedgerAllocator is planned on a copy, so unallocated currencies are not registered.
+func PlanLedgerMigration(from, to LedgerEncoder, currencies ...*Currency) []LedgerMigration {
+	from, to = sideEffectFree(from), sideEffectFree(to)
+	if len(currencies) == 0 {
+		currencies = registeredCurrencies()
+	}
+	plans := make([]LedgerMigration, 0, len(currencies))
+	targets := make(map[LedgerCode]*Currency, len(currencies))
+	for _, cur := range currencies {
+		plan := LedgerMigration{Currency: cur}
+		if plan.From, plan.Err = from.EncodeLedger(cur.code, cur.decimal); plan.Err == nil {
+			plan.To, plan.Err = to.EncodeLedger(cur.code, cur.decimal)
+		}
+		if plan.Err == nil {
+			if existing, ok := targets[plan.To]; ok && existing.code != cur.code {
+				plan.Err = fmt.Errorf("%w: %s and %s share ledger %d", ErrLedgerCodeCollision, cur.code, existing.code, plan.To)
+			} else {
+				targets[plan.To] = cur
+			}
+		}
+		plans = append(plans, plan)
+	}
+	return plans
+}
@@ -0,0 +1,146 @@
+package tbdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHashLedgerEncoder(t *testing.T) {
+	enc := HashLedgerEncoder{}
+	tests := []struct {
+		name    string
+		code    string
+		decimal uint8
+		want    LedgerCode
+	}{
+		{"IDR", "IDR", 2, 102181327},
+		{"BTC", "BTC", 8, 108112912},
+		{"short code", "US", 2, 102302800},
+		{"unsupported char as padding", "US$", 2, 102302800},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := enc.EncodeLedger(tt.code, tt.decimal)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got, "EncodeLedger(%q, %d) returned unexpected result", tt.code, tt.decimal)
+		})
+	}
+
+	code, decimal, err := enc.DecodeLedger(102181327)
+	assert.NoError(t, err)
+	assert.Equal(t, "IDR", code)
+	assert.Equal(t, uint8(2), decimal)
+
+	hashed, err := enc.EncodeLedger("USDT", 6)
+	assert.NoError(t, err)
+	_, _, err = enc.DecodeLedger(hashed)
+	assert.ErrorIs(t, err, ErrUnknownLedgerCode, "hashed ledger must not be reversible")
+}
+
+func TestBase36LedgerEncoder(t *testing.T) {
+	enc := Base36LedgerEncoder{}
+	tests := []struct {
+		name    string
+		code    string
+		want    LedgerCode
+		wantErr error
+	}{
+		{"single digit", "0", 1, nil},
+		{"single letter", "Z", 36, nil},
+		{"two chars", "00", 37, nil},
+		{"IDR", "IDR", 25_156, nil},
+		{"max", "ZZZZZZ", 2_238_976_116, nil},
+		{"unsupported char", "US$", 0, ErrInvalidCurrencyCode},
+		{"too long", "ABCDEFG", 0, ErrInvalidCurrencyCode},
+		{"empty", "", 0, ErrInvalidCurrencyCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := enc.EncodeLedger(tt.code, 0)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr, "EncodeLedger(%q) must return error", tt.code)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got, "EncodeLedger(%q) returned unexpected result", tt.code)
+		})
+	}
+
+	// Reversible for registered codes, e.g. USDT & IDR.
+	for _, cur := range []*Currency{USDT, IDR} {
+		ledger, err := enc.EncodeLedger(cur.code, cur.decimal)
+		assert.NoError(t, err)
+		code, decimal, err := enc.DecodeLedger(ledger)
+		assert.NoError(t, err)
+		assert.Equal(t, cur.code, code)
+		assert.Equal(t, cur.decimal, decimal)
+	}
+	_, _, err := enc.DecodeLedger(2_238_976_117)
+	assert.ErrorIs(t, err, ErrUnknownLedgerCode, "ledger beyond 6 chars must not decode")
+}
+
+func TestLedgerAllocator(t *testing.T) {
+	alloc := NewLedgerAllocator(nil)
+	ledger, err := alloc.Register("US", 2)
+	assert.NoError(t, err)
+	again, err := alloc.EncodeLedger("US", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, ledger, again, "registered currency must keep its ledger")
+
+	_, err = alloc.Register("US$", 2)
+	assert.ErrorIs(t, err, ErrLedgerCodeCollision)
+	_, err = alloc.EncodeLedger("US", 8)
+	assert.ErrorIs(t, err, ErrCurrencyCodeRegistered)
+
+	// Resolve the collision by pinning another ledger.
+	assert.NoError(t, alloc.Assign("US$", 2, 102999999))
+	code, decimal, err := alloc.DecodeLedger(102999999)
+	assert.NoError(t, err)
+	assert.Equal(t, "US$", code)
+	assert.Equal(t, uint8(2), decimal)
+}
+
+func TestSetLedgerEncoder(t *testing.T) {
+	defer func() { assert.NoError(t, SetLedgerEncoder(nil)) }()
+
+	before := IDR.EncodeLedger()
+	assert.NoError(t, SetLedgerEncoder(Base36LedgerEncoder{}))
+	assert.Equal(t, LedgerCode(25_156), IDR.EncodeLedger())
+	cur, err := ParseLedgerCode(25_156)
+	assert.NoError(t, err)
+	assert.Same(t, IDR, cur)
+	assert.Equal(t, "decimal=2, currency=IDR", IDR.DecodeLedger())
+
+	assert.NoError(t, SetLedgerEncoder(nil))
+	assert.Equal(t, before, IDR.EncodeLedger())
+}
+
+func TestPlanLedgerMigration(t *testing.T) {
+	plans := PlanLedgerMigration(HashLedgerEncoder{}, Base36LedgerEncoder{}, IDR, newCurrency("US$", 2))
+	assert.Len(t, plans, 2)
+	assert.True(t, plans[0].Changed())
+	assert.Equal(t, IDR.EncodeLedger(), plans[0].From)
+	assert.Equal(t, LedgerCode(25_156), plans[0].To)
+	assert.ErrorIs(t, plans[1].Err, ErrInvalidCurrencyCode)
+	assert.False(t, plans[1].Changed())
+
+	// Planning does not register on allocators.
+	alloc := NewLedgerAllocator(nil)
+	plans = PlanLedgerMigration(HashLedgerEncoder{}, alloc, IDR, USD)
+	assert.Len(t, plans, 2)
+	assert.NoError(t, plans[0].Err)
+	assert.NoError(t, plans[1].Err)
+	assert.Empty(t, alloc.byCode, "allocator must be unchanged")
+	assert.Empty(t, alloc.byLedger, "allocator must be unchanged")
+	_, _, err := alloc.DecodeLedger(plans[0].To)
+	assert.ErrorIs(t, err, ErrUnknownLedgerCode)
+
+	// Same encoder, nothing changes.
+	for _, plan := range PlanLedgerMigration(HashLedgerEncoder{}, HashLedgerEncoder{}) {
+		assert.NoError(t, plan.Err, "registered currency %s must encode", plan.Currency.Code())
+		assert.False(t, plan.Changed())
+	}
+}
@@ -0,0 +1,157 @@
+package tbdb
+
+import "fmt"
+
+// Money is an immutable amount of minor units in a currency, e.g. 2000050 IDR is IDR 20,000.50.
+// Arithmetic between Money values checks the currency, so BTC can never be added to IDR.
+// The zero value has no currency and only equals itself.
+type Money struct {
+	currency *Currency
+	minor    Uint128
+}
+
+// NewMoney creates Money of minor units in the currency.
+func NewMoney(cur *Currency, minor Uint128) Money {
+	return Money{currency: cur, minor: minor}
+}
+
+// Money creates Money of minor units in the currency, e.g. cents for USD.
+func (c *Currency) Money(minor Uint128) Money {
+	return NewMoney(c, minor)
+}
+
+// ParseMoney parses exact decimal string, e.g. "20000.50", into Money.
+// Fraction digits beyond the currency decimal are rounded with the given mode,
+// RoundingUnnecessary rejects them instead.
+//
+//	price, err := tbdb.IDR.ParseMoney("20000.50", tbdb.RoundingUnnecessary)
+func (c *Currency) ParseMoney(val string, mode RoundingMode) (Money, error) {
+	minor, err := parseDecimal(val, c.decimal, mode)
+	if err != nil {
+		return Money{}, fmt.Errorf("parse %s money %q: %w", c.code, val, err)
+	}
+	return NewMoney(c, minor), nil
+}
+
+// MoneyFromAmount converts an Amount into Money of the currency.
+// Returns ErrCurrencyAmountMismatch if the amount was created from another currency.
+func (c *Currency) MoneyFromAmount(a Amount) (Money, error) {
+	if a == nil {
+		return Money{}, ErrMonetaryMustNotBeNil
+	}
+	if amount, ok := a.(*amountCurrency); ok && !sameCurrency(c, amount.curr) {
+		return Money{}, fmt.Errorf("%w: %s amount as %s", ErrCurrencyAmountMismatch, amount.curr.code, c.code)
+	}
+	return NewMoney(c, a.ToUint128FromValue()), nil
+}
+
+// Currency returns the currency, nil for the zero value.
+func (m Money) Currency() *Currency { return m.currency }
+
+// Minor returns the value in minor units.
+func (m Money) Minor() Uint128 { return m.minor }
+
+// IsZero reports whether the value is zero, regardless of the currency.
+func (m Money) IsZero() bool { return m.minor.IsZero() }
+
+// Amount returns the value as Amount of the currency, e.g. for TransferData.Amount.
+func (m Money) Amount() Amount {
+	if m.currency == nil {
+		return nil
+	}
+	return m.currency.NewAmountFromMinorUnits(m.minor)
+}
+
+// sameCurrency reports whether a and b are the same currency.
+func sameCurrency(a, b *Currency) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a == b || (a.code == b.code && a.decimal == b.decimal)
+}
+
+// check returns ErrCurrencyAmountMismatch if o is of another currency.
+func (m Money) check(o Money) error {
+	if sameCurrency(m.currency, o.currency) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s and %s", ErrCurrencyAmountMismatch, m.code(), o.code())
+}
+
+// code returns the currency code, "<nil>" for the zero value.
+func (m Money) code() string {
+	if m.currency == nil {
+		return "<nil>"
+	}
+	return m.currency.code
+}
+
+// Add returns m + o.
+// Returns ErrCurrencyAmountMismatch on mixed currencies and ErrUint128Overflow on overflow.
+func (m Money) Add(o Money) (Money, error) {
+	if err := m.check(o); err != nil {
+		return Money{}, err
+	}
+	sum, overflow := m.minor.Add(o.minor)
+	if overflow {
+		return Money{}, ErrUint128Overflow
+	}
+	return NewMoney(m.currency, sum), nil
+}
+
+// Sub returns m - o.
+// Returns ErrCurrencyAmountMismatch on mixed currencies and ErrNegativeAmount if o is greater than m.
+func (m Money) Sub(o Money) (Money, error) {
+	if err := m.check(o); err != nil {
+		return Money{}, err
+	}
+	diff, underflow := m.minor.Sub(o.minor)
+	if underflow {
+		return Money{}, ErrNegativeAmount
+	}
+	return NewMoney(m.currency, diff), nil
+}
+
+// Compare compares m and o.
+// Returns -1 if m < o, 0 if m == o, 1 if m > o, and ErrCurrencyAmountMismatch on mixed currencies.
+func (m Money) Compare(o Money) (int, error) {
+	if err := m.check(o); err != nil {
+		return 0, err
+	}
+	return m.minor.Compare(o.minor), nil
+}
+
+// Equal reports whether m and o have the same currency and value.
+func (m Money) Equal(o Money) bool {
+	return sameCurrency(m.currency, o.currency) && m.minor == o.minor
+}
+
+// MulRatio multiplies m by an exact rational factor and rounds with the given mode.
+func (m Money) MulRatio(r Ratio, mode RoundingMode) (Money, error) {
+	result, err := mulDiv(m.minor, r.Num, r.Den, mode)
+	if err != nil {
+		return Money{}, err
+	}
+	return NewMoney(m.currency, result.Value), nil
+}
+
+// Format formats m with the given options, e.g. "IDR 20,000.50".
+func (m Money) Format(opts FormatOptions) string {
+	if m.currency == nil {
+		return m.minor.DecimalString()
+	}
+	return defaultLocale.Format(m.currency, m.minor, opts)
+}
+
+// FormatWith formats m with the given locale-aware formatter.
+func (m Money) FormatWith(f Formatter, opts FormatOptions) string {
+	if m.currency == nil {
+		return m.minor.DecimalString()
+	}
+	return f.Format(m.currency, m.minor, opts)
+}
+
+// String implements fmt.Stringer, e.g. "IDR 20,000.50".
+func (m Money) String() string {
+	return m.Format(FormatOptions{})
+}
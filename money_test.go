@@ -0,0 +1,81 @@
+package tbdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoneyArithmetic(t *testing.T) {
+	maxUint128 := Uint128{Hi: ^uint64(0), Lo: ^uint64(0)}
+	idr := newCurrency("IDR", 2) // Same code & decimal as IDR, but another instance.
+
+	tests := []struct {
+		name    string
+		op      func(a, b Money) (Money, error)
+		a, b    Money
+		want    Money
+		wantErr error
+	}{
+		{"add", Money.Add, IDR.Money(Uint128FromUint64(150)), IDR.Money(Uint128FromUint64(50)), IDR.Money(Uint128FromUint64(200)), nil},
+		{"add equal currency", Money.Add, IDR.Money(Uint128FromUint64(1)), idr.Money(Uint128FromUint64(1)), IDR.Money(Uint128FromUint64(2)), nil},
+		{"add mismatch", Money.Add, IDR.Money(Uint128FromUint64(1)), BTC.Money(Uint128FromUint64(1)), Money{}, ErrCurrencyAmountMismatch},
+		{"add zero value", Money.Add, IDR.Money(Uint128FromUint64(1)), Money{}, Money{}, ErrCurrencyAmountMismatch},
+		{"add overflow", Money.Add, IDR.Money(maxUint128), IDR.Money(Uint128FromUint64(1)), Money{}, ErrUint128Overflow},
+		{"sub", Money.Sub, USD.Money(Uint128FromUint64(150)), USD.Money(Uint128FromUint64(50)), USD.Money(Uint128FromUint64(100)), nil},
+		{"sub mismatch", Money.Sub, USD.Money(Uint128FromUint64(150)), EUR.Money(Uint128FromUint64(50)), Money{}, ErrCurrencyAmountMismatch},
+		{"sub negative", Money.Sub, USD.Money(Uint128FromUint64(50)), USD.Money(Uint128FromUint64(150)), Money{}, ErrNegativeAmount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.op(tt.a, tt.b)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr, "operation must return error")
+				return
+			}
+			assert.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "operation returned unexpected result %s", got)
+		})
+	}
+
+	cmp, err := IDR.Money(Uint128FromUint64(1)).Compare(IDR.Money(Uint128FromUint64(2)))
+	assert.NoError(t, err)
+	assert.Equal(t, -1, cmp, "Compare returned unexpected result")
+	_, err = IDR.Money(Uint128FromUint64(1)).Compare(BTC.Money(Uint128FromUint64(1)))
+	assert.ErrorIs(t, err, ErrCurrencyAmountMismatch)
+	assert.False(t, IDR.Money(Uint128FromUint64(1)).Equal(USD.Money(Uint128FromUint64(1))))
+
+	fee, err := IDR.Money(Uint128FromUint64(123450)).MulRatio(NewRatio(7, 1000), RoundingHalfUp)
+	assert.NoError(t, err)
+	assert.Equal(t, "IDR 8.64", fee.String(), "MulRatio returned unexpected result")
+}
+
+func TestMoneyAmountAdapters(t *testing.T) {
+	price, err := IDR.ParseMoney("20000.50", RoundingUnnecessary)
+	assert.NoError(t, err)
+	assert.Equal(t, Uint128FromUint64(2_000_050), price.Minor())
+	assert.Equal(t, IDR, price.Currency())
+	assert.Equal(t, "IDR 20,000.50", price.String())
+
+	_, err = IDR.ParseMoney("1.005", RoundingUnnecessary)
+	assert.ErrorIs(t, err, ErrRoundingNecessary)
+
+	amount := price.Amount()
+	assert.Equal(t, Uint128FromUint64(2_000_050), amount.ToUint128FromValue())
+	assert.Equal(t, "IDR 20,000.50", amount.Uint128ToString())
+	assert.Nil(t, Money{}.Amount(), "Amount of zero value must be nil")
+
+	got, err := IDR.MoneyFromAmount(amount)
+	assert.NoError(t, err)
+	assert.True(t, price.Equal(got), "MoneyFromAmount returned unexpected result")
+
+	got, err = USD.MoneyFromAmount(USD.NewAmountFromFloat64(1.5))
+	assert.NoError(t, err)
+	assert.Equal(t, Uint128FromUint64(150), got.Minor(), "MoneyFromAmount must use float64 value")
+
+	_, err = BTC.MoneyFromAmount(amount)
+	assert.ErrorIs(t, err, ErrCurrencyAmountMismatch)
+	_, err = BTC.MoneyFromAmount(nil)
+	assert.ErrorIs(t, err, ErrMonetaryMustNotBeNil)
+}
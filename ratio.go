@@ -0,0 +1,137 @@
+package tbdb
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Ratio defines an exact rational factor Num/Den, e.g. 7/1000 for 0.7%.
+type Ratio struct {
+	// Num is the numerator.
+	Num Uint128
+	// Den is the denominator, must not be zero.
+	Den Uint128
+}
+
+// NewRatio creates an exact rational factor num/den.
+func NewRatio(num, den uint64) Ratio {
+	return Ratio{Num: Uint128FromUint64(num), Den: Uint128FromUint64(den)}
+}
+
+// ParseRatio parses an exact decimal string into rational factor, e.g. "0.025" becomes 25/1000.
+//
+//	fee, err := tbdb.ParseRatio("0.7")
+func ParseRatio(s string) (Ratio, error) {
+	_, fracPart, _ := strings.Cut(strings.TrimSpace(s), ".")
+	if len(fracPart) > math.MaxUint8 {
+		return Ratio{}, ErrUint128Overflow
+	}
+	num, err := parseDecimal(s, uint8(len(fracPart)), RoundingUnnecessary)
+	if err != nil {
+		return Ratio{}, err
+	}
+	den := Uint128FromUint64(1)
+	for range fracPart {
+		var overflow bool
+		if den, overflow = mulAdd64(den, 10, 0); overflow {
+			return Ratio{}, ErrUint128Overflow
+		}
+	}
+	return Ratio{Num: num, Den: den}, nil
+}
+
+// RatioFromFloat64 converts float64 into rational factor through its shortest decimal representation,
+// so 0.1 becomes exactly 1/10 instead of its binary approximation.
+func RatioFromFloat64(f float64) (Ratio, error) {
+	if f < 0 {
+		return Ratio{}, ErrNegativeAmount
+	}
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return Ratio{}, ErrInvalidDecimal
+	}
+	return ParseRatio(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// Inverse returns Den/Num.
+func (r Ratio) Inverse() Ratio { return Ratio{Num: r.Den, Den: r.Num} }
+
+// IsZero returns true if the ratio value is zero.
+func (r Ratio) IsZero() bool { return r.Num.IsZero() }
+
+// String returns the ratio as "num/den" in decimal.
+func (r Ratio) String() string { return decimalDigits(r.Num) + "/" + decimalDigits(r.Den) }
+
+// RoundResult defines a rounded arithmetic result alongside its remainder, so calculations can be audited.
+// The exact result is Quotient + Remainder/Divisor.
+type RoundResult struct {
+	// Value is the rounded result.
+	Value Uint128
+	// Quotient is the truncated result.
+	Quotient Uint128
+	// Remainder is the remainder of the exact division.
+	Remainder Uint128
+	// Divisor is the divisor of the exact division.
+	Divisor Uint128
+	// Mode is the applied rounding mode.
+	Mode RoundingMode
+}
+
+// Rounded returns true if Value differs from the exact result.
+func (r RoundResult) Rounded() bool { return !r.Remainder.IsZero() }
+
+// mulDiv returns val*num/den rounded with mode, the intermediate product is not limited to 128 bits.
+func mulDiv(val, num, den Uint128, mode RoundingMode) (RoundResult, error) {
+	if den.IsZero() {
+		return RoundResult{}, ErrDivisionByZero
+	}
+
+	// Fast path: the product fits in 128 bits.
+	if product, overflow := val.Mul(num); !overflow {
+		quotient, rem := product.QuoRem(den)
+		// rem < den, so comparing rem against den-rem is comparing 2*rem against den without overflow.
+		half, _ := den.Sub(rem)
+		return roundResult(quotient, rem, den, rem.Compare(half), mode)
+	}
+
+	// Slow path: with pooled big.Int.
+	product := bigIntPool.Get().(*big.Int)
+	divisor := bigIntPool.Get().(*big.Int)
+	remainder := bigIntPool.Get().(*big.Int)
+	defer func() {
+		bigIntPool.Put(product)
+		bigIntPool.Put(divisor)
+		bigIntPool.Put(remainder)
+	}()
+	product.Mul(val.BigInt(), num.BigInt())
+	divisor.Set(den.BigInt())
+	product.QuoRem(product, divisor, remainder)
+	quotient, err := Uint128FromBigInt(product)
+	if err != nil {
+		return RoundResult{}, ErrUint128Overflow
+	}
+	rem, err := Uint128FromBigInt(remainder)
+	if err != nil {
+		return RoundResult{}, err
+	}
+
+	// Compare the remainder against half of the divisor.
+	return roundResult(quotient, rem, den, remainder.Lsh(remainder, 1).Cmp(divisor), mode)
+}
+
+// roundResult rounds the truncated quotient with mode, cmpHalf compares the remainder against half of the divisor.
+func roundResult(quotient, rem, den Uint128, cmpHalf int, mode RoundingMode) (RoundResult, error) {
+	up, err := mode.roundUp(quotient.Lo&1 == 1, !rem.IsZero(), cmpHalf)
+	if err != nil {
+		return RoundResult{}, err
+	}
+	result := RoundResult{Value: quotient, Quotient: quotient, Remainder: rem, Divisor: den, Mode: mode}
+	if up {
+		var overflow bool
+		if result.Value, overflow = quotient.Add(Uint128FromUint64(1)); overflow {
+			return RoundResult{}, ErrUint128Overflow
+		}
+	}
+	return result, nil
+}
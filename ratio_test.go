@@ -0,0 +1,116 @@
+package tbdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRatio(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Ratio
+		wantErr error
+	}{
+		{"integer", "3", NewRatio(3, 1), nil},
+		{"fraction", "0.025", NewRatio(25, 1000), nil},
+		{"trailing zeros", "2.50", NewRatio(250, 100), nil},
+		{"negative", "-0.5", Ratio{}, ErrNegativeAmount},
+		{"invalid", "1/2", Ratio{}, ErrInvalidDecimal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRatio(tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr, "ParseRatio(%q) must return error", tt.input)
+				return
+			}
+			assert.NoError(t, err, "Unexpected error for input %q", tt.input)
+			assert.Equal(t, tt.want, got, "ParseRatio(%q) returned unexpected result", tt.input)
+		})
+	}
+
+	ratio, err := RatioFromFloat64(0.1)
+	assert.NoError(t, err)
+	assert.Equal(t, NewRatio(1, 10), ratio, "RatioFromFloat64 must use the shortest decimal representation")
+}
+
+func TestMulDivRounding(t *testing.T) {
+	tests := []struct {
+		name string
+		val  uint64
+		num  uint64
+		den  uint64
+		mode RoundingMode
+		want uint64
+		rem  uint64
+	}{
+		{"exact", 1000, 7, 1000, RoundingUnnecessary, 7, 0},
+		{"half even down", 25, 1, 10, RoundingHalfEven, 2, 5},
+		{"half even up", 35, 1, 10, RoundingHalfEven, 4, 5},
+		{"half up", 25, 1, 10, RoundingHalfUp, 3, 5},
+		{"half down", 25, 1, 10, RoundingHalfDown, 2, 5},
+		{"half down above", 26, 1, 10, RoundingHalfDown, 3, 6},
+		{"ceiling", 21, 1, 10, RoundingCeiling, 3, 1},
+		{"floor", 29, 1, 10, RoundingFloor, 2, 9},
+		{"truncate", 29, 1, 10, RoundingTruncate, 2, 9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mulDiv(Uint128FromUint64(tt.val), Uint128FromUint64(tt.num), Uint128FromUint64(tt.den), tt.mode)
+			assert.NoError(t, err)
+			assert.Equal(t, Uint128FromUint64(tt.want), got.Value, "rounded value")
+			assert.Equal(t, Uint128FromUint64(tt.rem), got.Remainder, "remainder")
+			assert.Equal(t, Uint128FromUint64(tt.den), got.Divisor, "divisor")
+		})
+	}
+
+	_, err := mulDiv(Uint128FromUint64(25), Uint128FromUint64(1), Uint128FromUint64(10), RoundingUnnecessary)
+	assert.ErrorIs(t, err, ErrRoundingNecessary)
+	_, err = mulDiv(Uint128FromUint64(25), Uint128FromUint64(1), Uint128{}, RoundingHalfEven)
+	assert.ErrorIs(t, err, ErrDivisionByZero)
+	_, err = mulDiv(Uint128{Hi: ^uint64(0)}, Uint128FromUint64(2), Uint128FromUint64(1), RoundingHalfEven)
+	assert.ErrorIs(t, err, ErrUint128Overflow)
+
+	// Intermediate product beyond 128 bits.
+	got, err := mulDiv(Uint128{Hi: ^uint64(0)}, Uint128FromUint64(4), Uint128FromUint64(8), RoundingUnnecessary)
+	assert.NoError(t, err)
+	assert.Equal(t, Uint128{Hi: ^uint64(0) >> 1, Lo: 1 << 63}, got.Value)
+}
+
+func TestAmountRatioArithmetic(t *testing.T) {
+	var amount Amount = IDR.NewAmountFromMinorUnits(Uint128FromUint64(1000))
+
+	// 0.29 is 0.28999999999999998 in binary float64.
+	got, err := amount.Mul(0.29)
+	assert.NoError(t, err)
+	assert.Equal(t, Uint128FromUint64(290), got, "Mul must not carry binary float error")
+
+	got, err = amount.Percentage(0.7)
+	assert.NoError(t, err)
+	assert.Equal(t, Uint128FromUint64(7), got)
+
+	got, err = amount.Div(3)
+	assert.NoError(t, err)
+	assert.Equal(t, Uint128FromUint64(333), got)
+
+	got, err = amount.DivRatio(NewRatio(3, 1), RoundingCeiling)
+	assert.NoError(t, err)
+	assert.Equal(t, Uint128FromUint64(334), got)
+
+	got, err = IDR.NewAmountFromMinorUnits(Uint128FromUint64(123_450)).PercentageRatio(NewRatio(7, 10), RoundingHalfUp)
+	assert.NoError(t, err)
+	assert.Equal(t, Uint128FromUint64(864), got)
+
+	result, err := amount.MulDiv(Uint128FromUint64(1), Uint128FromUint64(3), RoundingHalfEven)
+	assert.NoError(t, err)
+	assert.True(t, result.Rounded())
+	assert.Equal(t, Uint128FromUint64(333), result.Value)
+	assert.Equal(t, Uint128FromUint64(1), result.Remainder)
+
+	_, err = amount.DivRatio(NewRatio(0, 1), RoundingHalfEven)
+	assert.ErrorIs(t, err, ErrDivisionByZero)
+}
@@ -0,0 +1,71 @@
+package tbdb
+
+// RoundingMode defines how a value that needs more precision than available is rounded.
+// Amounts are never negative, so RoundingCeiling rounds away from zero and RoundingFloor
+// behaves as RoundingTruncate.
+type RoundingMode uint8
+
+// Enum of rounding mode.
+const (
+	// RoundingUnnecessary rejects any value that needs rounding with ErrRoundingNecessary.
+	RoundingUnnecessary RoundingMode = iota
+	// RoundingHalfEven rounds to the nearest neighbor, ties to the even neighbor (banker's rounding).
+	RoundingHalfEven
+	// RoundingHalfUp rounds to the nearest neighbor, ties away from zero.
+	RoundingHalfUp
+	// RoundingHalfDown rounds to the nearest neighbor, ties towards zero.
+	RoundingHalfDown
+	// RoundingCeiling rounds towards positive infinity.
+	RoundingCeiling
+	// RoundingFloor rounds towards negative infinity.
+	RoundingFloor
+	// RoundingTruncate discards the remainder.
+	RoundingTruncate
+)
+
+// String returns the rounding mode name.
+func (m RoundingMode) String() string {
+	switch m {
+	case RoundingUnnecessary:
+		return "unnecessary"
+	case RoundingHalfEven:
+		return "half_even"
+	case RoundingHalfUp:
+		return "half_up"
+	case RoundingHalfDown:
+		return "half_down"
+	case RoundingCeiling:
+		return "ceiling"
+	case RoundingFloor:
+		return "floor"
+	case RoundingTruncate:
+		return "truncate"
+	default:
+		return "unknown"
+	}
+}
+
+// roundUp reports whether a truncated quotient must be incremented by one.
+// The remainder is described by hasRemainder and cmpHalf, the comparison of the remainder
+// against half of the divisor (-1 below, 0 exactly half, 1 above).
+func (m RoundingMode) roundUp(quotientOdd, hasRemainder bool, cmpHalf int) (bool, error) {
+	if !hasRemainder {
+		return false, nil
+	}
+	switch m {
+	case RoundingUnnecessary:
+		return false, ErrRoundingNecessary
+	case RoundingHalfEven:
+		return cmpHalf > 0 || (cmpHalf == 0 && quotientOdd), nil
+	case RoundingHalfUp:
+		return cmpHalf >= 0, nil
+	case RoundingHalfDown:
+		return cmpHalf > 0, nil
+	case RoundingCeiling:
+		return true, nil
+	case RoundingFloor, RoundingTruncate:
+		return false, nil
+	default:
+		return false, ErrUnknownRoundingMode
+	}
+}
@@ -0,0 +1,880 @@
+package tbdb
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	tb "github.com/tigerbeetle/tigerbeetle-go"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+// StatementEntryKind defines how a statement entry changes the account balance.
+type StatementEntryKind uint8
+
+// Enum of statement entry kind.
+const (
+	// Single-phase transfer, changes the posted balance.
+	StatementEntryPosted StatementEntryKind = iota + 1
+	// Pending transfer, holds the amount until it is posted, voided or expired.
+	StatementEntryPending
+	// Post of a pending transfer, releases the hold and posts the amount.
+	StatementEntryPostPending
+	// Void of a pending transfer, releases the hold.
+	StatementEntryVoidPending
+	// Expiry of a pending transfer, releases the hold. Has no transfer ID, see AccountStatement.PendingID.
+	StatementEntryExpired
+)
+
+// String implements fmt.Stringer.
+func (k StatementEntryKind) String() string {
+	switch k {
+	case StatementEntryPosted:
+		return "posted"
+	case StatementEntryPending:
+		return "pending"
+	case StatementEntryPostPending:
+		return "post_pending"
+	case StatementEntryVoidPending:
+		return "void_pending"
+	case StatementEntryExpired:
+		return "expired"
+	}
+	return "unknown"
+}
+
+// statementEntryKindOf returns the entry kind of transfer flags.
+func statementEntryKindOf(flags TransferFlags) StatementEntryKind {
+	switch {
+	case flags.PostPendingTransfer:
+		return StatementEntryPostPending
+	case flags.VoidPendingTransfer:
+		return StatementEntryVoidPending
+	case flags.Pending:
+		return StatementEntryPending
+	}
+	return StatementEntryPosted
+}
+
+// AccountStatement defines account's statement record.
+type AccountStatement struct {
+	// ID is unique transfer identifier, zero for expired entries.
+	ID Uint128
+	// DebitAccountID is debit account id.
+	DebitAccountID Uint128
+	// CreditAccountID is credit account id.
+	CreditAccountID Uint128
+	// PendingID reference to pending transfer id of post, void & expired entries.
+	PendingID Uint128
+	// Amount is the transfer amount, Debit and Credit are its effect on the balance.
+	Amount Amount
+	// Debit is the monetary amount moving the balance to the debit side,
+	// e.g. a pending debit or a posted debit decreases a credit normal balance.
+	Debit Amount
+	// Credit is the monetary amount moving the balance to the credit side,
+	// e.g. a void of a pending debit increases a credit normal balance back.
+	Credit Amount
+	// BalanceBefore is the monetary of balance before, the magnitude of SignedBalanceBefore.
+	BalanceBefore Amount
+	// BalanceAfter is the monetary of balance after, the magnitude of SignedBalanceAfter.
+	BalanceAfter Amount
+	// SignedBalanceBefore is the available balance before the entry on the account normal side.
+	SignedBalanceBefore SignedAmount
+	// SignedBalanceAfter is the available balance after the entry on the account normal side.
+	SignedBalanceAfter SignedAmount
+	// UserData128 is 128-bit user-defined data.
+	UserData128 Uint128
+	// UserData64 is 64-bit user-defined data.
+	UserData64 uint64
+	// Timestamp is the time transfer was created; in nanoseconds since UNIX epoch.
+	Timestamp uint64
+	// UserData32 is 32-bit user-defined data.
+	UserData32 uint32
+	// Ledger this account belongs to.
+	Ledger uint32
+	// This is a user-defined enum denoting the reason for (or category of) the transfer.
+	Code uint16
+	// Flags defines the behavior of transfer.
+	Flags TransferFlags
+	// Kind defines how the entry changes the balance.
+	Kind StatementEntryKind
+	// Additional is user-defined data that be writen by given closure.
+	Additional any
+}
+
+// StatementDiagnostic defines a statement row that can not be reconciled.
+type StatementDiagnostic struct {
+	// Index of the entry in Statement.Entries, -1 for a balance row without entry.
+	Index int
+	// Timestamp of the transfer or balance row; in nanoseconds since UNIX epoch.
+	Timestamp uint64
+	// TransferID is the transfer identifier, zero for a balance row without transfer.
+	TransferID Uint128
+	// Err describes the row, wraps ErrStatementUnreconciled.
+	Err error
+}
+
+// Statement defines the account statement of the filter time range.
+type Statement struct {
+	// AccountID is unique identifier of account.
+	AccountID Uint128
+	// NormalSide is the account normal side of the balances.
+	NormalSide NormalSide
+	// Opening is the available balance before the first entry.
+	Opening SignedAmount
+	// Closing is the available balance after the last entry.
+	Closing SignedAmount
+	// TotalDebits is the sum of entries debit.
+	TotalDebits Amount
+	// TotalCredits is the sum of entries credit.
+	TotalCredits Amount
+	// Entries is the statement rows, in the filter order.
+	Entries []AccountStatement
+	// Diagnostics reports the rows that can not be reconciled, the entries are kept.
+	Diagnostics []StatementDiagnostic
+}
+
+// StatementClosureFn is closure function of statement.
+type StatementClosureFn func(
+	id, userData128 Uint128,
+	userData64 uint64,
+	userData32 uint32,
+	code uint16,
+	flags TransferFlags,
+	timestamp uint64,
+) any
+
+// GetAccountStatements fetchs the account statements record involving a given account,
+// see GetStatement for the balances and the diagnostics of rows that can not be reconciled.
+// The max size of AccountStatement array result is equal to TigerBeetleMaxBatch,
+// even the filter.Limit set to > TigerBeetleMaxBatch.
+func (i *Instance) GetAccountStatements(
+	filter AccountTransferFilter,
+	closureFn ...StatementClosureFn,
+) ([]AccountStatement, error) {
+	statement, err := i.GetStatement(filter, closureFn...)
+	if err != nil {
+		return nil, err
+	}
+	return statement.Entries, nil
+}
+
+// GetStatement fetchs the account statement involving a given account, merging the transfers
+// and the historical balances by timestamp. The account must have the History flag, otherwise returns
+// ErrAccountWithoutHistory.
+//
+// Balances are the available balance on the account normal side, see Account.AvailableBalance:
+// pending transfers decreasing the balance are debited, or credited for debit normal accounts, when created,
+// posts & voids settle the difference and expiries release the hold.
+// Rows that can not be reconciled, e.g. a transfer without balance row, are kept and reported in Diagnostics.
+//
+// The max size of Entries is equal to TigerBeetleMaxBatch, even the filter.Limit set to > TigerBeetleMaxBatch,
+// see StreamStatement for longer time ranges.
+func (i *Instance) GetStatement(filter AccountTransferFilter, closureFn ...StatementClosureFn) (*Statement, error) {
+	account, cln, err := i.statementAccount(&filter)
+	if err != nil {
+		return nil, err
+	}
+	var clsFn StatementClosureFn
+	if len(closureFn) > 0 {
+		clsFn = closureFn[0]
+	}
+	statement, _, err := i.statementPage(cln, account, filter, clsFn)
+	return statement, err
+}
+
+// StreamStatement streams the account statement entries of the whole time range to fn, page by page,
+// see GetStatement. Returns the statement without entries: the balances & totals of the time range
+// and the diagnostics indexed by the streamed order. Stops at the first fn error.
+func (i *Instance) StreamStatement(
+	filter AccountTransferFilter,
+	fn func(entry AccountStatement) error,
+	closureFn ...StatementClosureFn,
+) (*Statement, error) {
+	account, cln, err := i.statementAccount(&filter)
+	if err != nil {
+		return nil, err
+	}
+	var clsFn StatementClosureFn
+	if len(closureFn) > 0 {
+		clsFn = closureFn[0]
+	}
+	return i.streamStatement(cln, account, filter, fn, clsFn)
+}
+
+// streamStatement streams the pages of the validated filter.
+func (i *Instance) streamStatement(
+	cln tb.Client,
+	account Account,
+	filter AccountTransferFilter,
+	fn func(entry AccountStatement) error,
+	clsFn StatementClosureFn,
+) (*Statement, error) {
+	var (
+		stream                    *Statement
+		count                     int
+		totalDebits, totalCredits Uint128
+	)
+	for {
+		page, cursor, err := i.statementPage(cln, account, filter, clsFn)
+		if err != nil {
+			return nil, err
+		}
+		if stream == nil {
+			stream = &Statement{
+				AccountID:  page.AccountID,
+				NormalSide: page.NormalSide,
+				Opening:    page.Opening,
+				Closing:    page.Closing,
+			}
+		}
+
+		// Balances of the time range, pages are in the filter order.
+		if filter.Flags.Reversed {
+			stream.Opening = page.Opening
+		} else {
+			stream.Closing = page.Closing
+		}
+		for _, diagnostic := range page.Diagnostics {
+			if diagnostic.Index >= 0 {
+				diagnostic.Index += count
+			}
+			stream.Diagnostics = append(stream.Diagnostics, diagnostic)
+		}
+		for _, entry := range page.Entries {
+			if err := fn(entry); err != nil {
+				return nil, err
+			}
+		}
+		count += len(page.Entries)
+		var overflowDebits, overflowCredits bool
+		totalDebits, overflowDebits = totalDebits.Add(page.TotalDebits.ToUint128FromValue())
+		totalCredits, overflowCredits = totalCredits.Add(page.TotalCredits.ToUint128FromValue())
+		if overflowDebits || overflowCredits {
+			stream.Diagnostics = append(stream.Diagnostics, StatementDiagnostic{
+				Index: -1,
+				Err:   fmt.Errorf("%w: totals overflow", ErrStatementUnreconciled),
+			})
+		}
+
+		// Next page.
+		if cursor == 0 {
+			break
+		}
+		if filter.Flags.Reversed {
+			filter.TimeMax = time.Unix(0, int64(cursor-1))
+		} else {
+			filter.TimeMin = time.Unix(0, int64(cursor+1))
+		}
+		if filter.TimeMin.After(filter.TimeMax) {
+			break
+		}
+	}
+	stream.TotalDebits = filter.Monetary.SetUint128Value(totalDebits)
+	stream.TotalCredits = filter.Monetary.SetUint128Value(totalCredits)
+	return stream, nil
+}
+
+// statementAccount returns the filter account, for the monetary & normal side, and validates the filter.
+// Returns ErrAccountWithoutHistory if the account has no historical balances to build from.
+func (i *Instance) statementAccount(filter *AccountTransferFilter) (Account, tb.Client, error) {
+	if filter.AccountID.IsZero() {
+		return Account{}, nil, ErrAccountIDMustNotBeZero
+	}
+	results, err := i.LookupAccountsOrdered([]AccountLookup{{ID: filter.AccountID, Monetary: filter.Monetary}})
+	if err != nil {
+		return Account{}, nil, err
+	}
+	switch {
+	case !results[0].Found:
+		return Account{}, nil, fmt.Errorf("%w: %s", ErrAccountNotFound, filter.AccountID.String())
+	case results[0].Err != nil:
+		return Account{}, nil, results[0].Err
+	case !results[0].Account.Flags.History:
+		return Account{}, nil, fmt.Errorf("%w: %s", ErrAccountWithoutHistory, filter.AccountID.String())
+	}
+	account := results[0].Account
+	if filter.Monetary == nil {
+		filter.Monetary = account.CreditsPosted
+	}
+	cln, err := i.accountTransferFilterValidate(filter)
+	if err != nil {
+		return Account{}, nil, err
+	}
+	return account, cln, nil
+}
+
+// statementPage returns the statement page of the validated filter,
+// with the timestamp of the next page cursor, zero if it is the last page.
+func (i *Instance) statementPage(
+	cln tb.Client,
+	account Account,
+	filter AccountTransferFilter,
+	clsFn StatementClosureFn,
+) (*Statement, uint64, error) {
+	// Get balances & transfers.
+	balances, err := i.GetHisotricalBalances(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	transfers, err := i.GetAccountTransfers(filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	src := newStatementSource(account, filter, balances, transfers)
+
+	// Balance before the page, if the records are continuous; truncated reversed pages start at the cursor.
+	if src.continuous() {
+		start := filter.TimeMin
+		if filter.Flags.Reversed && src.cursor != 0 {
+			start = time.Unix(0, int64(src.cursor))
+		}
+		opening, err := i.balanceBefore(filter, start)
+		if err != nil {
+			return nil, 0, err
+		}
+		src.opening = &opening
+	}
+
+	// Pending transfers of posts & voids.
+	if src.pendings, err = statementPendings(cln, filter.Monetary, src.transfers); err != nil {
+		return nil, 0, err
+	}
+	return buildStatement(src, clsFn), src.cursor, nil
+}
+
+// balanceBefore returns the latest balance of the filter account before the time, zero if none.
+func (i *Instance) balanceBefore(filter AccountTransferFilter, before time.Time) (accountSides, error) {
+	opening := accountSides{monetary: filter.Monetary}
+	if before.UnixNano() <= 1 {
+		return opening, nil
+	}
+	balances, err := i.GetHisotricalBalances(AccountTransferFilter{
+		TimeMin:   time.Unix(0, 1),
+		TimeMax:   before.Add(-time.Nanosecond),
+		AccountID: filter.AccountID,
+		Limit:     1,
+		Monetary:  filter.Monetary,
+		Flags:     AccountFilterFlags{Debits: true, Credits: true, Reversed: true},
+	})
+	if err != nil || len(balances) == 0 {
+		return opening, err
+	}
+	return balances[0].sides(), nil
+}
+
+// statementPendings returns the pending transfers of posts & voids by id,
+// looking up the ones outside the transfers.
+func statementPendings(cln tb.Client, monetary Amount, transfers []AccountTransfer) (map[Uint128]AccountTransfer, error) {
+	pendings := make(map[Uint128]AccountTransfer)
+	for _, transfer := range transfers {
+		if transfer.Flags.Pending {
+			pendings[transfer.ID] = transfer
+		}
+	}
+	var (
+		tbIds []types.Uint128
+		seen  = make(map[Uint128]struct{})
+	)
+	for _, transfer := range transfers {
+		if !transfer.Flags.PostPendingTransfer && !transfer.Flags.VoidPendingTransfer {
+			continue
+		}
+		if _, ok := pendings[transfer.PendingID]; ok {
+			continue
+		}
+		if _, ok := seen[transfer.PendingID]; ok {
+			continue
+		}
+		seen[transfer.PendingID] = struct{}{}
+		tbIds = append(tbIds, toBinding(transfer.PendingID))
+	}
+	if len(tbIds) == 0 {
+		return pendings, nil
+	}
+
+	tbTransfers, err := cln.LookupTransfers(tbIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, transfer := range tbTransfers {
+		pendings[fromBinding(transfer.ID)] = toAccountTransfer(transfer, monetary)
+	}
+	return pendings, nil
+}
+
+// statementSource defines the records of a statement, in chronological order.
+type statementSource struct {
+	account   Account
+	filter    AccountTransferFilter
+	balances  []AccountBalance
+	transfers []AccountTransfer
+	// pendings is the pending transfers of posts & voids, by id.
+	pendings map[Uint128]AccountTransfer
+	// opening is the balance before the first record, nil if the records are not continuous.
+	opening *accountSides
+	// cursor is the timestamp the page ends at in the filter order, zero if it is the last page.
+	cursor uint64
+}
+
+// newStatementSource returns the records in chronological order. Records truncated by the filter limit
+// end the page where both balances & transfers are complete, the rest is on the next page.
+func newStatementSource(
+	account Account,
+	filter AccountTransferFilter,
+	balances []AccountBalance,
+	transfers []AccountTransfer,
+) statementSource {
+	src := statementSource{account: account, filter: filter, balances: balances, transfers: transfers}
+	balancesTruncated := len(balances) > 0 && len(balances) >= int(filter.Limit)
+	transfersTruncated := len(transfers) > 0 && len(transfers) >= int(filter.Limit)
+	reversed := filter.Flags.Reversed
+	if reversed {
+		slices.Reverse(src.balances)
+		slices.Reverse(src.transfers)
+	}
+
+	// Page end, the earliest last record or the latest first record if reversed.
+	edge := func(ts uint64) {
+		if src.cursor == 0 || (reversed && ts > src.cursor) || (!reversed && ts < src.cursor) {
+			src.cursor = ts
+		}
+	}
+	switch {
+	case balancesTruncated && reversed:
+		edge(src.balances[0].Timestamp)
+	case balancesTruncated:
+		edge(src.balances[len(src.balances)-1].Timestamp)
+	}
+	switch {
+	case transfersTruncated && reversed:
+		edge(src.transfers[0].Timestamp)
+	case transfersTruncated:
+		edge(src.transfers[len(src.transfers)-1].Timestamp)
+	}
+	if src.cursor == 0 {
+		return src
+	}
+	outside := func(ts uint64) bool {
+		if reversed {
+			return ts < src.cursor
+		}
+		return ts > src.cursor
+	}
+	src.balances = slices.DeleteFunc(src.balances, func(b AccountBalance) bool { return outside(b.Timestamp) })
+	src.transfers = slices.DeleteFunc(src.transfers, func(t AccountTransfer) bool { return outside(t.Timestamp) })
+	return src
+}
+
+// continuous reports whether the records are every change of the account from the page start,
+// i.e. not filtered by user data, code or side.
+func (s statementSource) continuous() bool {
+	filter := s.filter
+	return filter.UserData128.IsZero() && filter.UserData64 == 0 && filter.UserData32 == 0 && filter.Code == 0 &&
+		filter.Flags.Debits && filter.Flags.Credits
+}
+
+// equal reports whether both have the same debits & credits.
+func (s accountSides) equal(o accountSides) bool {
+	return s.debitsPending == o.debitsPending && s.debitsPosted == o.debitsPosted &&
+		s.creditsPending == o.creditsPending && s.creditsPosted == o.creditsPosted
+}
+
+// statementDelta defines the change of an entry to the account debits or credits.
+type statementDelta struct {
+	// debit reports the account is the debit side.
+	debit                          bool
+	pendingAdd, pendingSub, posted Uint128
+}
+
+// fields returns the pending & posted fields of the delta side.
+func (d statementDelta) fields(s *accountSides) (pending, posted *Uint128) {
+	if d.debit {
+		return &s.debitsPending, &s.debitsPosted
+	}
+	return &s.creditsPending, &s.creditsPosted
+}
+
+// apply returns the sides after the delta, false on overflow or underflow.
+func (d statementDelta) apply(s accountSides) (accountSides, bool) {
+	pending, posted := d.fields(&s)
+	p, overflowPending := pending.Add(d.pendingAdd)
+	p, underflow := p.Sub(d.pendingSub)
+	q, overflowPosted := posted.Add(d.posted)
+	*pending, *posted = p, q
+	return s, !overflowPending && !underflow && !overflowPosted
+}
+
+// revert returns the sides before the delta, false if the sides do not include the delta.
+func (d statementDelta) revert(s accountSides) (accountSides, bool) {
+	pending, posted := d.fields(&s)
+	p, overflow := pending.Add(d.pendingSub)
+	p, underflowPending := p.Sub(d.pendingAdd)
+	q, underflowPosted := posted.Sub(d.posted)
+	*pending, *posted = p, q
+	return s, !overflow && !underflowPending && !underflowPosted
+}
+
+// change returns the increase & decrease of the available balance on the normal side, see accountSides.available.
+func (d statementDelta) change(side NormalSide) (increase, decrease Uint128) {
+	// Pending changes on the normal side are not available yet.
+	if d.debit == (side == NormalSideDebit) {
+		return d.posted, Uint128{}
+	}
+	out, _ := d.posted.Add(d.pendingAdd)
+	if diff, negative := d.pendingSub.Sub(out); !negative {
+		return diff, Uint128{}
+	}
+	diff, _ := out.Sub(d.pendingSub)
+	return Uint128{}, diff
+}
+
+// releaseOf returns the delta of a single pending release from before to after, false if it is not a release.
+func releaseOf(before, after accountSides) (statementDelta, bool) {
+	if before.debitsPosted != after.debitsPosted || before.creditsPosted != after.creditsPosted {
+		return statementDelta{}, false
+	}
+	debits, underflowDebits := before.debitsPending.Sub(after.debitsPending)
+	credits, underflowCredits := before.creditsPending.Sub(after.creditsPending)
+	switch {
+	case underflowDebits || underflowCredits:
+		return statementDelta{}, false
+	case !debits.IsZero() && credits.IsZero():
+		return statementDelta{debit: true, pendingSub: debits}, true
+	case debits.IsZero() && !credits.IsZero():
+		return statementDelta{pendingSub: credits}, true
+	}
+	return statementDelta{}, false
+}
+
+// amountValue returns the amount value, zero if nil.
+func amountValue(a Amount) Uint128 {
+	if a == nil {
+		return Uint128{}
+	}
+	return a.ToUint128FromValue()
+}
+
+// statementBuilder defines the state of statement merge.
+type statementBuilder struct {
+	src       statementSource
+	side      NormalSide
+	clsFn     StatementClosureFn
+	statement *Statement
+	// running is the balance before the next record, if known.
+	running accountSides
+	known   bool
+	// holds is the unresolved pending transfers with timeout, by id.
+	holds                     map[Uint128]AccountTransfer
+	totalDebits, totalCredits Uint128
+}
+
+// buildStatement merges the transfers & balances of the source by timestamp, in a single pass.
+func buildStatement(src statementSource, clsFn StatementClosureFn) *Statement {
+	b := &statementBuilder{
+		src:   src,
+		side:  src.account.NormalSide(),
+		clsFn: clsFn,
+		holds: make(map[Uint128]AccountTransfer),
+	}
+	zero := b.zero()
+	b.statement = &Statement{
+		AccountID:  src.account.ID,
+		NormalSide: b.side,
+		Opening:    SignedAmount{Magnitude: zero},
+		Closing:    SignedAmount{Magnitude: zero},
+		Entries:    make([]AccountStatement, 0, len(src.transfers)),
+	}
+	if src.opening != nil {
+		b.running, b.known = *src.opening, true
+		b.statement.Opening = b.running.available(b.side)
+		b.statement.Closing = b.statement.Opening
+	}
+
+	// Merge.
+	transfers, balances := src.transfers, src.balances
+	for ti, bi := 0, 0; ti < len(transfers) || bi < len(balances); {
+		switch {
+		case ti < len(transfers) && bi < len(balances) && transfers[ti].Timestamp == balances[bi].Timestamp:
+			b.transfer(transfers[ti], &balances[bi])
+			ti++
+			bi++
+		case ti < len(transfers) && (bi == len(balances) || transfers[ti].Timestamp < balances[bi].Timestamp):
+			b.transfer(transfers[ti], nil)
+			ti++
+		default:
+			b.balance(balances[bi])
+			bi++
+		}
+	}
+
+	// Totals.
+	b.statement.TotalDebits = src.filter.Monetary.SetUint128Value(b.totalDebits)
+	b.statement.TotalCredits = src.filter.Monetary.SetUint128Value(b.totalCredits)
+
+	// In the filter order.
+	if src.filter.Flags.Reversed {
+		count := len(b.statement.Entries)
+		slices.Reverse(b.statement.Entries)
+		slices.Reverse(b.statement.Diagnostics)
+		for idx := range b.statement.Diagnostics {
+			if b.statement.Diagnostics[idx].Index >= 0 {
+				b.statement.Diagnostics[idx].Index = count - 1 - b.statement.Diagnostics[idx].Index
+			}
+		}
+	}
+	return b.statement
+}
+
+// zero returns zero amount of the monetary.
+func (b *statementBuilder) zero() Amount {
+	return b.src.filter.Monetary.SetUint128Value(Uint128{})
+}
+
+// diagnosef appends the diagnostic of the row.
+func (b *statementBuilder) diagnosef(index int, timestamp uint64, id Uint128, format string, args ...any) {
+	b.statement.Diagnostics = append(b.statement.Diagnostics, StatementDiagnostic{
+		Index:      index,
+		Timestamp:  timestamp,
+		TransferID: id,
+		Err:        fmt.Errorf("%w: %s", ErrStatementUnreconciled, fmt.Sprintf(format, args...)),
+	})
+}
+
+// delta returns the change of the transfer to the account, false if the pending transfer is unknown.
+func (b *statementBuilder) delta(transfer AccountTransfer, kind StatementEntryKind) (statementDelta, bool) {
+	delta := statementDelta{debit: transfer.DebitAccountID == b.src.account.ID}
+	amount := amountValue(transfer.Amount)
+	switch kind {
+	case StatementEntryPosted:
+		delta.posted = amount
+	case StatementEntryPending:
+		delta.pendingAdd = amount
+	case StatementEntryPostPending, StatementEntryVoidPending:
+		pending, ok := b.src.pendings[transfer.PendingID]
+		if !ok {
+			return delta, false
+		}
+		delta.pendingSub = amountValue(pending.Amount)
+		if kind == StatementEntryPostPending {
+			delta.posted = amount
+		}
+	}
+	return delta, true
+}
+
+// transfer appends the entry of the transfer with its balance row, nil if none.
+func (b *statementBuilder) transfer(transfer AccountTransfer, row *AccountBalance) {
+	index, kind := len(b.statement.Entries), statementEntryKindOf(transfer.Flags)
+	delta, deltaOK := b.delta(transfer, kind)
+
+	// Balance after from the row, before from the delta.
+	var (
+		before, after     accountSides
+		beforeOK, afterOK bool
+	)
+	if row != nil {
+		after, afterOK = row.sides(), true
+	} else {
+		b.diagnosef(index, transfer.Timestamp, transfer.ID, "no balance row of %s transfer", kind)
+	}
+	if afterOK && deltaOK {
+		if before, beforeOK = delta.revert(after); !beforeOK {
+			b.diagnosef(index, transfer.Timestamp, transfer.ID, "balance row does not include %s transfer", kind)
+		}
+	}
+
+	// Continuity with the previous record.
+	if b.known {
+		if beforeOK && !before.equal(b.running) {
+			b.diagnosef(index, transfer.Timestamp, transfer.ID, "balance changed without record before %s transfer", kind)
+		}
+		if !beforeOK {
+			before, beforeOK = b.running, true
+		}
+		if !afterOK && deltaOK {
+			if after, afterOK = delta.apply(before); !afterOK {
+				b.diagnosef(index, transfer.Timestamp, transfer.ID, "%s transfer exceeds the balance", kind)
+			}
+		}
+	}
+
+	// Released amount of unknown pending transfer from the balances.
+	if !deltaOK {
+		if beforeOK && afterOK {
+			pendingBefore, _ := delta.fields(&before)
+			pendingAfter, _ := delta.fields(&after)
+			released, underflow := pendingBefore.Sub(*pendingAfter)
+			if deltaOK = !underflow; deltaOK {
+				delta.pendingSub = released
+				if kind == StatementEntryPostPending {
+					delta.posted = amountValue(transfer.Amount)
+				}
+			}
+		}
+		if !deltaOK {
+			b.diagnosef(index, transfer.Timestamp, transfer.ID, "pending transfer %s not found", transfer.PendingID.String())
+		}
+	}
+
+	// Holds.
+	switch kind {
+	case StatementEntryPending:
+		if transfer.Timeout > 0 {
+			b.holds[transfer.ID] = transfer
+		}
+	case StatementEntryPostPending, StatementEntryVoidPending:
+		delete(b.holds, transfer.PendingID)
+	}
+
+	b.append(AccountStatement{
+		ID:              transfer.ID,
+		DebitAccountID:  transfer.DebitAccountID,
+		CreditAccountID: transfer.CreditAccountID,
+		PendingID:       transfer.PendingID,
+		Amount:          transfer.Amount,
+		UserData128:     transfer.UserData128,
+		UserData64:      transfer.UserData64,
+		Timestamp:       transfer.Timestamp,
+		UserData32:      transfer.UserData32,
+		Ledger:          transfer.Ledger,
+		Code:            transfer.Code,
+		Flags:           transfer.Flags,
+		Kind:            kind,
+	}, delta, deltaOK, before, beforeOK, after, afterOK)
+}
+
+// balance appends the expiry entry of a balance row without transfer, or reports it.
+func (b *statementBuilder) balance(row AccountBalance) {
+	after := row.sides()
+
+	// Release of a hold.
+	var (
+		before accountSides
+		delta  statementDelta
+		ok     bool
+		hold   AccountTransfer
+		held   bool
+	)
+	if b.known {
+		before = b.running
+		if delta, ok = releaseOf(before, after); ok {
+			hold, held = b.expiredHold(row.Timestamp, func(d statementDelta) bool {
+				return d.debit == delta.debit && d.pendingSub == delta.pendingSub
+			})
+		}
+	} else {
+		hold, held = b.expiredHold(row.Timestamp, func(d statementDelta) bool {
+			_, reverted := d.revert(after)
+			return reverted
+		})
+		if held {
+			delta = b.holdRelease(hold)
+			before, ok = delta.revert(after)
+		}
+	}
+	if !ok {
+		b.diagnosef(-1, row.Timestamp, Uint128{}, "balance row without transfer")
+		b.running, b.known = after, b.src.opening != nil
+		return
+	}
+
+	// Expiry entry, of unknown pending transfer if not held, e.g. created before the time range.
+	entry := AccountStatement{
+		Amount:    b.src.filter.Monetary.SetUint128Value(delta.pendingSub),
+		Timestamp: row.Timestamp,
+		Ledger:    b.src.account.Ledger,
+		Kind:      StatementEntryExpired,
+	}
+	if delta.debit {
+		entry.DebitAccountID = b.src.account.ID
+	} else {
+		entry.CreditAccountID = b.src.account.ID
+	}
+	if held {
+		delete(b.holds, hold.ID)
+		entry.DebitAccountID, entry.CreditAccountID, entry.PendingID = hold.DebitAccountID, hold.CreditAccountID, hold.ID
+		entry.UserData128, entry.UserData64, entry.UserData32 = hold.UserData128, hold.UserData64, hold.UserData32
+		entry.Ledger, entry.Code = hold.Ledger, hold.Code
+	}
+	b.append(entry, delta, true, before, true, after, true)
+}
+
+// holdRelease returns the delta of the hold expiry.
+func (b *statementBuilder) holdRelease(hold AccountTransfer) statementDelta {
+	return statementDelta{debit: hold.DebitAccountID == b.src.account.ID, pendingSub: amountValue(hold.Amount)}
+}
+
+// expiredHold returns the earliest hold expired at the timestamp whose release matches.
+func (b *statementBuilder) expiredHold(timestamp uint64, match func(statementDelta) bool) (AccountTransfer, bool) {
+	var (
+		found     AccountTransfer
+		ok        bool
+		expiresAt uint64
+	)
+	for _, hold := range b.holds {
+		at := hold.Timestamp + uint64(hold.Timeout)*uint64(time.Second)
+		if at > timestamp || !match(b.holdRelease(hold)) {
+			continue
+		}
+		if !ok || at < expiresAt || (at == expiresAt && hold.Timestamp < found.Timestamp) {
+			found, ok, expiresAt = hold, true, at
+		}
+	}
+	return found, ok
+}
+
+// append appends the entry with its debit, credit & balances, then advances the running balance.
+func (b *statementBuilder) append(
+	entry AccountStatement,
+	delta statementDelta,
+	deltaOK bool,
+	before accountSides,
+	beforeOK bool,
+	after accountSides,
+	afterOK bool,
+) {
+	zero := b.zero()
+	entry.Debit, entry.Credit = zero, zero
+	entry.SignedBalanceBefore, entry.SignedBalanceAfter = SignedAmount{Magnitude: zero}, SignedAmount{Magnitude: zero}
+	if deltaOK {
+		// Increases are on the normal side.
+		debit, credit := delta.change(b.side)
+		if b.side == NormalSideCredit {
+			debit, credit = credit, debit
+		}
+		entry.Debit = b.src.filter.Monetary.SetUint128Value(debit)
+		entry.Credit = b.src.filter.Monetary.SetUint128Value(credit)
+		var overflowDebits, overflowCredits bool
+		b.totalDebits, overflowDebits = b.totalDebits.Add(debit)
+		b.totalCredits, overflowCredits = b.totalCredits.Add(credit)
+		if overflowDebits || overflowCredits {
+			b.diagnosef(len(b.statement.Entries), entry.Timestamp, entry.ID, "totals overflow")
+		}
+	}
+	if beforeOK {
+		entry.SignedBalanceBefore = before.available(b.side)
+		if len(b.statement.Entries) == 0 {
+			b.statement.Opening = entry.SignedBalanceBefore
+		}
+	}
+	if afterOK {
+		entry.SignedBalanceAfter = after.available(b.side)
+		b.statement.Closing = entry.SignedBalanceAfter
+	}
+	entry.BalanceBefore, entry.BalanceAfter = entry.SignedBalanceBefore.Magnitude, entry.SignedBalanceAfter.Magnitude
+	b.running, b.known = after, afterOK && b.src.opening != nil
+
+	if b.clsFn != nil {
+		entry.Additional = b.clsFn(
+			entry.ID,
+			entry.UserData128,
+			entry.UserData64,
+			entry.UserData32,
+			entry.Code,
+			entry.Flags,
+			entry.Timestamp,
+		)
+	}
+	b.statement.Entries = append(b.statement.Entries, entry)
+}
@@ -0,0 +1,606 @@
+package tbdb
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// StatementFormat defines the file format of statement export.
+type StatementFormat uint8
+
+// Enum of statement format.
+const (
+	// Comma-separated values with a header row.
+	StatementFormatCSV StatementFormat = iota + 1
+	// JSON object per line.
+	StatementFormatJSONL
+	// SWIFT MT940 customer statement message.
+	StatementFormatMT940
+	// ISO 20022 camt.053.001.02 bank to customer statement.
+	StatementFormatCAMT053
+)
+
+// String implements fmt.Stringer.
+func (f StatementFormat) String() string {
+	switch f {
+	case StatementFormatCSV:
+		return "csv"
+	case StatementFormatJSONL:
+		return "jsonl"
+	case StatementFormatMT940:
+		return "mt940"
+	case StatementFormatCAMT053:
+		return "camt.053"
+	}
+	return "unknown"
+}
+
+// StatementReference defines the reference & narrative fields of an exported entry.
+type StatementReference struct {
+	// Reference identifies the entry, e.g. MT940 :61: reference for the account owner or camt.053 EndToEndId.
+	Reference string
+	// Narrative describes the entry, e.g. MT940 :86: information or camt.053 AddtlNtryInf.
+	Narrative string
+}
+
+// StatementMapperFn maps the transfer code & user data of an entry to its reference & narrative.
+type StatementMapperFn func(entry AccountStatement) StatementReference
+
+// StatementExportOptions defines statement export parameters.
+type StatementExportOptions struct {
+	// Format is the file format. Required.
+	Format StatementFormat
+	// Mapper maps entries to reference & narrative.
+	// Optional; default reference is the transfer ID and narrative is the entry kind & code.
+	Mapper StatementMapperFn
+	// Formatter formats CSV & JSONL amounts. Optional; default formats "20000.50" without grouping & code.
+	Formatter Formatter
+	// Location of the dates. Optional; default UTC.
+	Location *time.Location
+	// StatementID identifies the statement, MT940 :20: & camt.053 MsgId & Stmt/Id, truncated to 16 & 35 characters.
+	// Optional; default "STMT" and the filter TimeMax date, e.g. "STMT20250824".
+	StatementID string
+	// AccountIdentification identifies the account, MT940 :25: & camt.053 Acct/Id.
+	// Optional; default the account ID in hex.
+	AccountIdentification string
+	// SequenceNumber is the statement number, MT940 :28C: & camt.053 ElctrncSeqNb. Optional; default 1.
+	SequenceNumber uint32
+	// CreatedAt is the creation time of camt.053 message. Optional; default now.
+	CreatedAt time.Time
+}
+
+// ExportStatement streams the account statement of the whole time range to w in the options format,
+// see StreamStatement. Returns the statement without entries.
+//
+// MT940 & camt.053 opening and closing balances are the available balances of the account at TimeMin & TimeMax,
+// so the filter should not be filtered by user data or code. Entries without effect on the balance,
+// e.g. pending transfers on the account normal side, are not exported in these formats, and the entries are
+// in chronological order between the balances, filter.Flags.Reversed is ignored.
+func (i *Instance) ExportStatement(w io.Writer, filter AccountTransferFilter, options StatementExportOptions) (*Statement, error) {
+	if options.Format < StatementFormatCSV || options.Format > StatementFormatCAMT053 {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownStatementFormat, options.Format)
+	}
+	if options.Format == StatementFormatMT940 || options.Format == StatementFormatCAMT053 {
+		filter.Flags.Reversed = false
+	}
+	account, cln, err := i.statementAccount(&filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Balances of the time range.
+	opening, err := i.balanceBefore(filter, filter.TimeMin)
+	if err != nil {
+		return nil, err
+	}
+	closing, err := i.balanceBefore(filter, filter.TimeMax.Add(time.Nanosecond))
+	if err != nil {
+		return nil, err
+	}
+	side := account.NormalSide()
+	header := newStatementHeader(account, filter, options, opening.available(side), closing.available(side))
+
+	// Stream.
+	bw := bufio.NewWriter(w)
+	encoder, err := newStatementEncoder(bw, header)
+	if err != nil {
+		return nil, err
+	}
+	if err := encoder.begin(); err != nil {
+		return nil, err
+	}
+	statement, err := i.streamStatement(cln, account, filter, func(entry AccountStatement) error {
+		return encoder.entry(entry, header.mapper(entry))
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := encoder.end(); err != nil {
+		return nil, err
+	}
+	return statement, bw.Flush()
+}
+
+// statementHeader defines the statement export data known before the entries.
+type statementHeader struct {
+	StatementExportOptions
+	account          Account
+	currency         *Currency
+	timeMin, timeMax time.Time
+	opening, closing SignedAmount
+	mapper           StatementMapperFn
+}
+
+// newStatementHeader returns the header with the options defaults.
+func newStatementHeader(
+	account Account,
+	filter AccountTransferFilter,
+	options StatementExportOptions,
+	opening, closing SignedAmount,
+) statementHeader {
+	if options.Formatter == nil {
+		options.Formatter = Locale{DecimalSeparator: "."}
+	}
+	if options.Location == nil {
+		options.Location = time.UTC
+	}
+	if options.StatementID == "" {
+		options.StatementID = "STMT" + filter.TimeMax.In(options.Location).Format("20060102")
+	}
+	if options.AccountIdentification == "" {
+		options.AccountIdentification = account.ID.String()
+	}
+	if options.SequenceNumber == 0 {
+		options.SequenceNumber = 1
+	}
+	if options.CreatedAt.IsZero() {
+		options.CreatedAt = time.Now()
+	}
+	header := statementHeader{
+		StatementExportOptions: options,
+		account:                account,
+		timeMin:                filter.TimeMin.In(options.Location),
+		timeMax:                filter.TimeMax.In(options.Location),
+		opening:                opening,
+		closing:                closing,
+		mapper:                 options.Mapper,
+	}
+	if amount, ok := filter.Monetary.(*amountCurrency); ok {
+		header.currency = amount.curr
+	}
+	if header.mapper == nil {
+		header.mapper = defaultStatementMapper
+	}
+	return header
+}
+
+// defaultStatementMapper references the transfer ID, or the pending ID of expired entries, with kind & code narrative.
+func defaultStatementMapper(entry AccountStatement) StatementReference {
+	id := entry.ID
+	if id.IsZero() {
+		id = entry.PendingID
+	}
+	return StatementReference{Reference: id.String(), Narrative: fmt.Sprintf("%s code %d", entry.Kind, entry.Code)}
+}
+
+// currencyCode returns the ISO code, "XXX" for custom monetary.
+func (h statementHeader) currencyCode() string {
+	if h.currency == nil {
+		return "XXX"
+	}
+	return h.currency.code
+}
+
+// format formats the amount with the formatter without code, minor units for custom monetary.
+func (h statementHeader) format(f Formatter, a Amount, negative bool) string {
+	value := amountValue(a)
+	if h.currency != nil {
+		return f.Format(h.currency, value, FormatOptions{HideCode: true, Negative: negative})
+	}
+	if negative && !value.IsZero() {
+		return "-" + decimalDigits(value)
+	}
+	return decimalDigits(value)
+}
+
+// time returns the entry timestamp in the location.
+func (h statementHeader) time(timestamp uint64) time.Time {
+	return time.Unix(0, int64(timestamp)).In(h.Location)
+}
+
+// creditBalance reports whether the balance is on the credit side, e.g. a positive balance of credit normal account.
+func (h statementHeader) creditBalance(balance SignedAmount) bool {
+	return (h.account.NormalSide() == NormalSideCredit) != balance.Negative
+}
+
+// statementEncoder defines the writer of a statement export format.
+type statementEncoder interface {
+	begin() error
+	entry(entry AccountStatement, reference StatementReference) error
+	end() error
+}
+
+// newStatementEncoder returns the encoder of the header format.
+func newStatementEncoder(w io.Writer, header statementHeader) (statementEncoder, error) {
+	switch header.Format {
+	case StatementFormatCSV:
+		return &csvStatementEncoder{header: header, w: csv.NewWriter(w)}, nil
+	case StatementFormatJSONL:
+		return &jsonlStatementEncoder{header: header, enc: json.NewEncoder(w)}, nil
+	case StatementFormatMT940:
+		return &mt940StatementEncoder{header: header, w: w}, nil
+	case StatementFormatCAMT053:
+		return &camtStatementEncoder{header: header, enc: xml.NewEncoder(w), w: w}, nil
+	}
+	return nil, fmt.Errorf("%w: %d", ErrUnknownStatementFormat, header.Format)
+}
+
+// csvStatementEncoder writes a row per entry.
+type csvStatementEncoder struct {
+	header statementHeader
+	w      *csv.Writer
+}
+
+func (e *csvStatementEncoder) begin() error {
+	return e.w.Write([]string{
+		"date_time", "id", "kind", "pending_id", "debit_account_id", "credit_account_id", "code",
+		"reference", "narrative", "currency", "amount", "debit", "credit", "balance_before", "balance_after",
+	})
+}
+
+func (e *csvStatementEncoder) entry(entry AccountStatement, reference StatementReference) error {
+	h := e.header
+	return e.w.Write([]string{
+		h.time(entry.Timestamp).Format(time.RFC3339Nano),
+		entry.ID.String(),
+		entry.Kind.String(),
+		entry.PendingID.String(),
+		entry.DebitAccountID.String(),
+		entry.CreditAccountID.String(),
+		strconv.FormatUint(uint64(entry.Code), 10),
+		reference.Reference,
+		reference.Narrative,
+		h.currencyCode(),
+		h.format(h.Formatter, entry.Amount, false),
+		h.format(h.Formatter, entry.Debit, false),
+		h.format(h.Formatter, entry.Credit, false),
+		h.format(h.Formatter, entry.SignedBalanceBefore.Magnitude, entry.SignedBalanceBefore.Negative),
+		h.format(h.Formatter, entry.SignedBalanceAfter.Magnitude, entry.SignedBalanceAfter.Negative),
+	})
+}
+
+func (e *csvStatementEncoder) end() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// jsonlStatementEncoder writes a JSON object per entry.
+type jsonlStatementEncoder struct {
+	header statementHeader
+	enc    *json.Encoder
+}
+
+// jsonlStatementLine defines the JSON object of an entry.
+type jsonlStatementLine struct {
+	DateTime        string `json:"date_time"`
+	Timestamp       uint64 `json:"timestamp"`
+	ID              string `json:"id"`
+	Kind            string `json:"kind"`
+	PendingID       string `json:"pending_id"`
+	DebitAccountID  string `json:"debit_account_id"`
+	CreditAccountID string `json:"credit_account_id"`
+	UserData128     string `json:"user_data_128"`
+	UserData64      uint64 `json:"user_data_64"`
+	UserData32      uint32 `json:"user_data_32"`
+	Code            uint16 `json:"code"`
+	Reference       string `json:"reference"`
+	Narrative       string `json:"narrative"`
+	Currency        string `json:"currency"`
+	Amount          string `json:"amount"`
+	Debit           string `json:"debit"`
+	Credit          string `json:"credit"`
+	BalanceBefore   string `json:"balance_before"`
+	BalanceAfter    string `json:"balance_after"`
+}
+
+func (e *jsonlStatementEncoder) begin() error { return nil }
+
+func (e *jsonlStatementEncoder) entry(entry AccountStatement, reference StatementReference) error {
+	h := e.header
+	return e.enc.Encode(jsonlStatementLine{
+		DateTime:        h.time(entry.Timestamp).Format(time.RFC3339Nano),
+		Timestamp:       entry.Timestamp,
+		ID:              entry.ID.String(),
+		Kind:            entry.Kind.String(),
+		PendingID:       entry.PendingID.String(),
+		DebitAccountID:  entry.DebitAccountID.String(),
+		CreditAccountID: entry.CreditAccountID.String(),
+		UserData128:     entry.UserData128.String(),
+		UserData64:      entry.UserData64,
+		UserData32:      entry.UserData32,
+		Code:            entry.Code,
+		Reference:       reference.Reference,
+		Narrative:       reference.Narrative,
+		Currency:        h.currencyCode(),
+		Amount:          h.format(h.Formatter, entry.Amount, false),
+		Debit:           h.format(h.Formatter, entry.Debit, false),
+		Credit:          h.format(h.Formatter, entry.Credit, false),
+		BalanceBefore:   h.format(h.Formatter, entry.SignedBalanceBefore.Magnitude, entry.SignedBalanceBefore.Negative),
+		BalanceAfter:    h.format(h.Formatter, entry.SignedBalanceAfter.Magnitude, entry.SignedBalanceAfter.Negative),
+	})
+}
+
+func (e *jsonlStatementEncoder) end() error { return nil }
+
+// mt940Locale formats MT940 amounts, e.g. "20000,50".
+var mt940Locale = Locale{DecimalSeparator: ","}
+
+// mt940StatementEncoder writes MT940 fields, lines are separated by CRLF.
+type mt940StatementEncoder struct {
+	header statementHeader
+	w      io.Writer
+}
+
+// line writes a field line.
+func (e *mt940StatementEncoder) line(format string, args ...any) error {
+	_, err := fmt.Fprintf(e.w, format+"\r\n", args...)
+	return err
+}
+
+// amount formats the MT940 amount, the decimal comma is mandatory.
+func (e *mt940StatementEncoder) amount(a Amount) string {
+	amount := e.header.format(mt940Locale, a, false)
+	if !strings.Contains(amount, ",") {
+		amount += ","
+	}
+	return amount
+}
+
+// balance formats the MT940 balance, e.g. "C250824IDR20000,50".
+func (e *mt940StatementEncoder) balance(balance SignedAmount, date time.Time) string {
+	mark := "D"
+	if e.header.creditBalance(balance) {
+		mark = "C"
+	}
+	return mark + date.Format("060102") + e.header.currencyCode() + e.amount(balance.Magnitude)
+}
+
+func (e *mt940StatementEncoder) begin() error {
+	h := e.header
+	for _, err := range []error{
+		e.line(":20:%s", swiftText(h.StatementID, 16, true)),
+		e.line(":25:%s", swiftText(h.AccountIdentification, 35, true)),
+		e.line(":28C:%05d", h.SequenceNumber%100000),
+		e.line(":60F:%s", e.balance(h.opening, h.timeMin)),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *mt940StatementEncoder) entry(entry AccountStatement, reference StatementReference) error {
+	mark, amount := "C", entry.Credit
+	if amountValue(entry.Credit).IsZero() {
+		mark, amount = "D", entry.Debit
+	}
+	if amountValue(amount).IsZero() {
+		return nil
+	}
+
+	// Reference for the account owner and of the account servicing institution, the rightmost 16 characters.
+	date := e.header.time(entry.Timestamp)
+	ownerRef := swiftText(reference.Reference, 0, true)
+	if len(ownerRef) > 16 {
+		ownerRef = ownerRef[len(ownerRef)-16:]
+	}
+	if ownerRef == "" {
+		ownerRef = "NONREF"
+	}
+	id := entry.ID
+	if id.IsZero() {
+		id = entry.PendingID
+	}
+	servicerRef := id.String()
+	if len(servicerRef) > 16 {
+		servicerRef = servicerRef[len(servicerRef)-16:]
+	}
+	if err := e.line(":61:%s%s%s%sNTRF%s//%s", date.Format("060102"), date.Format("0102"), mark, e.amount(amount),
+		ownerRef, servicerRef); err != nil {
+		return err
+	}
+
+	// Narrative of up to 6 lines of 65 characters.
+	narrative := swiftText(reference.Narrative, 6*65, false)
+	if narrative == "" {
+		return nil
+	}
+	lines := make([]string, 0, 6)
+	for len(narrative) > 65 {
+		lines, narrative = append(lines, narrative[:65]), narrative[65:]
+	}
+	lines = append(lines, narrative)
+	return e.line(":86:%s", strings.Join(lines, "\r\n"))
+}
+
+func (e *mt940StatementEncoder) end() error {
+	if err := e.line(":62F:%s", e.balance(e.header.closing, e.header.timeMax)); err != nil {
+		return err
+	}
+	return e.line("-")
+}
+
+// swiftText returns the text in SWIFT X character set, other characters are replaced by space
+// or removed with the slashes & spaces if compact, then truncated to limit characters if limit > 0.
+func swiftText(s string, limit int, compact bool) string {
+	var builder strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', strings.ContainsRune("-?:().,'+", r):
+			builder.WriteRune(r)
+		case r == '/' || r == ' ':
+			if !compact {
+				builder.WriteRune(r)
+			}
+		case !compact:
+			builder.WriteByte(' ')
+		}
+	}
+	text := strings.TrimSpace(builder.String())
+	if limit > 0 && len(text) > limit {
+		text = text[:limit]
+	}
+	return text
+}
+
+// camtStatementEncoder writes camt.053.001.02 document, a statement of the entries.
+type camtStatementEncoder struct {
+	header statementHeader
+	enc    *xml.Encoder
+	w      io.Writer
+}
+
+// camtAmount defines the amount with currency.
+type camtAmount struct {
+	Currency string `xml:"Ccy,attr"`
+	Value    string `xml:",chardata"`
+}
+
+// camtBalance defines Bal element.
+type camtBalance struct {
+	Code      string     `xml:"Tp>CdOrPrtry>Cd"`
+	Amount    camtAmount `xml:"Amt"`
+	Indicator string     `xml:"CdtDbtInd"`
+	Date      string     `xml:"Dt>Dt"`
+}
+
+// camtEntry defines Ntry element.
+type camtEntry struct {
+	Amount            camtAmount `xml:"Amt"`
+	Indicator         string     `xml:"CdtDbtInd"`
+	Status            string     `xml:"Sts"`
+	BookingDate       string     `xml:"BookgDt>DtTm"`
+	ServicerReference string     `xml:"AcctSvcrRef,omitempty"`
+	TransactionCode   string     `xml:"BkTxCd>Prtry>Cd"`
+	EndToEndID        string     `xml:"NtryDtls>TxDtls>Refs>EndToEndId,omitempty"`
+	Information       string     `xml:"AddtlNtryInf,omitempty"`
+}
+
+// element encodes v as the named element.
+func (e *camtStatementEncoder) element(name string, v any) error {
+	return e.enc.EncodeElement(v, xml.StartElement{Name: xml.Name{Local: name}})
+}
+
+// start encodes the start of the named element.
+func (e *camtStatementEncoder) start(name string, attrs ...xml.Attr) error {
+	return e.enc.EncodeToken(xml.StartElement{Name: xml.Name{Local: name}, Attr: attrs})
+}
+
+// amount returns the amount with the currency, e.g. "20000.50".
+func (e *camtStatementEncoder) amount(a Amount) camtAmount {
+	return camtAmount{Currency: e.header.currencyCode(), Value: e.header.format(Locale{DecimalSeparator: "."}, a, false)}
+}
+
+// balance returns the balance of the type code, OPBD or CLBD.
+func (e *camtStatementEncoder) balance(code string, balance SignedAmount, date time.Time) camtBalance {
+	indicator := "DBIT"
+	if e.header.creditBalance(balance) {
+		indicator = "CRDT"
+	}
+	return camtBalance{Code: code, Amount: e.amount(balance.Magnitude), Indicator: indicator, Date: date.Format(time.DateOnly)}
+}
+
+func (e *camtStatementEncoder) begin() error {
+	h := e.header
+	if _, err := io.WriteString(e.w, xml.Header); err != nil {
+		return err
+	}
+	e.enc.Indent("", "  ")
+	createdAt := h.CreatedAt.In(h.Location).Format(time.RFC3339)
+	id := truncate(h.StatementID, 35)
+	for _, err := range []error{
+		e.start("Document", xml.Attr{Name: xml.Name{Local: "xmlns"}, Value: "urn:iso:std:iso:20022:tech:xsd:camt.053.001.02"}),
+		e.start("BkToCstmrStmt"),
+		e.element("GrpHdr", struct {
+			MessageID string `xml:"MsgId"`
+			CreatedAt string `xml:"CreDtTm"`
+		}{id, createdAt}),
+		e.start("Stmt"),
+		e.element("Id", id),
+		e.element("ElctrncSeqNb", h.SequenceNumber),
+		e.element("CreDtTm", createdAt),
+		e.element("FrToDt", struct {
+			From string `xml:"FrDtTm"`
+			To   string `xml:"ToDtTm"`
+		}{h.timeMin.Format(time.RFC3339), h.timeMax.Format(time.RFC3339)}),
+		e.element("Acct", struct {
+			ID       string `xml:"Id>Othr>Id"`
+			Currency string `xml:"Ccy"`
+		}{h.AccountIdentification, h.currencyCode()}),
+		e.element("Bal", e.balance("OPBD", h.opening, h.timeMin)),
+		e.element("Bal", e.balance("CLBD", h.closing, h.timeMax)),
+	} {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *camtStatementEncoder) entry(entry AccountStatement, reference StatementReference) error {
+	indicator, amount := "CRDT", entry.Credit
+	if amountValue(entry.Credit).IsZero() {
+		indicator, amount = "DBIT", entry.Debit
+	}
+	if amountValue(amount).IsZero() {
+		return nil
+	}
+	status := "BOOK"
+	if entry.Kind == StatementEntryPending {
+		status = "PDNG"
+	}
+	id := entry.ID
+	if id.IsZero() {
+		id = entry.PendingID
+	}
+	return e.element("Ntry", camtEntry{
+		Amount:            e.amount(amount),
+		Indicator:         indicator,
+		Status:            status,
+		BookingDate:       e.header.time(entry.Timestamp).Format(time.RFC3339),
+		ServicerReference: id.String(),
+		TransactionCode:   strconv.FormatUint(uint64(entry.Code), 10),
+		EndToEndID:        truncate(reference.Reference, 35),
+		Information:       truncate(reference.Narrative, 500),
+	})
+}
+
+func (e *camtStatementEncoder) end() error {
+	for _, name := range []string{"Stmt", "BkToCstmrStmt", "Document"} {
+		if err := e.enc.EncodeToken(xml.EndElement{Name: xml.Name{Local: name}}); err != nil {
+			return err
+		}
+	}
+	if err := e.enc.Flush(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(e.w, "\n")
+	return err
+}
+
+// truncate returns the first limit characters of s.
+func truncate(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit])
+}
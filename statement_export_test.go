@@ -0,0 +1,289 @@
+package tbdb
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+// testStatementFixture is a wallet with 10.00 IDR before the time range of 10-50 ns,
+// then credits 1.00 at 10, 20 & 40, debits 0.50 at 30 and holds 0.30 at 50.
+func testStatementFixture() fakeClient {
+	id, other := toBinding(Uint128FromUint64(1)), toBinding(Uint128FromUint64(2))
+	ledger := uint32(IDR.EncodeLedger())
+	value := func(v uint64) types.Uint128 { return toBinding(Uint128FromUint64(v)) }
+	c := fakeClient{
+		accounts: []types.Account{{
+			ID:     id,
+			Ledger: ledger,
+			Code:   uint16(AccountCategoryBalance),
+			Flags:  types.AccountFlags{History: true}.ToUint16(),
+		}},
+	}
+	transfer := func(ts uint64, debit bool, amount uint64, flags types.TransferFlags) {
+		t := types.Transfer{
+			ID:              value(100 + ts),
+			DebitAccountID:  other,
+			CreditAccountID: id,
+			Amount:          value(amount),
+			Ledger:          ledger,
+			Code:            7,
+			Flags:           flags.ToUint16(),
+			Timestamp:       ts,
+		}
+		if debit {
+			t.DebitAccountID, t.CreditAccountID = id, other
+		}
+		c.transfers = append(c.transfers, t)
+	}
+	balance := func(ts, debitsPending, debitsPosted, creditsPosted uint64) {
+		c.balances = append(c.balances, types.AccountBalance{
+			Timestamp:     ts,
+			DebitsPending: value(debitsPending),
+			DebitsPosted:  value(debitsPosted),
+			CreditsPosted: value(creditsPosted),
+		})
+	}
+	transfer(5, false, 1000, types.TransferFlags{})
+	balance(5, 0, 0, 1000)
+	transfer(10, false, 100, types.TransferFlags{})
+	balance(10, 0, 0, 1100)
+	transfer(20, false, 100, types.TransferFlags{})
+	balance(20, 0, 0, 1200)
+	transfer(30, true, 50, types.TransferFlags{})
+	balance(30, 0, 50, 1200)
+	transfer(40, false, 100, types.TransferFlags{})
+	balance(40, 0, 50, 1300)
+	transfer(50, true, 30, types.TransferFlags{Pending: true})
+	balance(50, 30, 50, 1300)
+	return c
+}
+
+func statementFakeFilter(limit uint32, reversed bool) AccountTransferFilter {
+	return AccountTransferFilter{
+		TimeMin:   time.Unix(0, 10),
+		TimeMax:   time.Unix(0, 60),
+		AccountID: Uint128FromUint64(1),
+		Limit:     limit,
+		Flags:     AccountFilterFlags{Reversed: reversed},
+	}
+}
+
+func TestStreamStatement(t *testing.T) {
+	for _, reversed := range []bool{false, true} {
+		fake := testStatementFixture()
+		i := &Instance{client: &fake}
+
+		var timestamps []uint64
+		statement, err := i.StreamStatement(statementFakeFilter(2, reversed), func(entry AccountStatement) error {
+			timestamps = append(timestamps, entry.Timestamp)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Empty(t, statement.Diagnostics)
+		assert.Greater(t, fake.pages, 2, "paged")
+
+		want := []uint64{10, 20, 30, 40, 50}
+		if reversed {
+			want = []uint64{50, 40, 30, 20, 10}
+		}
+		assert.Equal(t, want, timestamps)
+		assert.Equal(t, "IDR 10.00", statement.Opening.String())
+		assert.Equal(t, "IDR 12.20", statement.Closing.String())
+		assert.Equal(t, Uint128FromUint64(80), statement.TotalDebits.ToUint128FromValue())
+		assert.Equal(t, Uint128FromUint64(300), statement.TotalCredits.ToUint128FromValue())
+	}
+}
+
+func TestStreamStatementExpiry(t *testing.T) {
+	// Credit 1.00 at 55, then the hold of 50 expires at 60, so the pages of limit 2 split the hold & its expiry.
+	fake := modified(testStatementFixture(), func(c *fakeClient) {
+		c.transfers = append(c.transfers, types.Transfer{
+			ID:              toBinding(Uint128FromUint64(155)),
+			DebitAccountID:  toBinding(Uint128FromUint64(2)),
+			CreditAccountID: c.accounts[0].ID,
+			Amount:          toBinding(Uint128FromUint64(100)),
+			Ledger:          c.accounts[0].Ledger,
+			Code:            7,
+			Timestamp:       55,
+		})
+		last := c.balances[len(c.balances)-1]
+		credits := toBinding(Uint128FromUint64(1400))
+		c.balances = append(c.balances,
+			types.AccountBalance{Timestamp: 55, DebitsPending: last.DebitsPending, DebitsPosted: last.DebitsPosted, CreditsPosted: credits},
+			types.AccountBalance{Timestamp: 60, DebitsPosted: last.DebitsPosted, CreditsPosted: credits},
+		)
+	})
+
+	for _, reversed := range []bool{false, true} {
+		i := &Instance{client: &fake}
+		var entries []AccountStatement
+		filter := statementFakeFilter(2, reversed)
+		filter.TimeMax = time.Unix(0, 70)
+		statement, err := i.StreamStatement(filter, func(entry AccountStatement) error {
+			entries = append(entries, entry)
+			return nil
+		})
+		require.NoError(t, err)
+		assert.Empty(t, statement.Diagnostics, "reversed %t", reversed)
+		require.Len(t, entries, 7)
+
+		expiry := entries[len(entries)-1]
+		if reversed {
+			expiry = entries[0]
+		}
+		assert.Equal(t, StatementEntryExpired, expiry.Kind)
+		assert.Equal(t, "IDR 13.20", expiry.SignedBalanceBefore.String())
+		assert.Equal(t, "IDR 13.50", expiry.SignedBalanceAfter.String())
+		assert.Equal(t, "IDR 10.00", statement.Opening.String())
+		assert.Equal(t, "IDR 13.50", statement.Closing.String())
+		assert.Equal(t, Uint128FromUint64(80), statement.TotalDebits.ToUint128FromValue())
+		assert.Equal(t, Uint128FromUint64(430), statement.TotalCredits.ToUint128FromValue())
+	}
+}
+
+func TestExportStatement(t *testing.T) {
+	exportFilter := func(filter AccountTransferFilter, options StatementExportOptions) string {
+		fake := testStatementFixture()
+		i := &Instance{client: &fake}
+		var buf bytes.Buffer
+		_, err := i.ExportStatement(&buf, filter, options)
+		require.NoError(t, err)
+		return buf.String()
+	}
+	export := func(options StatementExportOptions) string {
+		return exportFilter(statementFakeFilter(2, false), options)
+	}
+	mapper := func(entry AccountStatement) StatementReference {
+		return StatementReference{Reference: "INV/" + entry.Kind.String(), Narrative: fmt.Sprintf("Top up code %d", entry.Code)}
+	}
+
+	t.Run("csv", func(t *testing.T) {
+		records, err := csv.NewReader(strings.NewReader(export(StatementExportOptions{
+			Format:    StatementFormatCSV,
+			Formatter: LocaleIdID,
+		}))).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, records, 6)
+		assert.Equal(t, "date_time", records[0][0])
+		assert.Equal(t, []string{"pending", "7", "pending code 7", "IDR", "0,30", "0,30", "0,00", "12,50", "12,20"},
+			[]string{records[5][2], records[5][6], records[5][8], records[5][9], records[5][10], records[5][11],
+				records[5][12], records[5][13], records[5][14]})
+	})
+
+	t.Run("jsonl", func(t *testing.T) {
+		scanner := bufio.NewScanner(strings.NewReader(export(StatementExportOptions{Format: StatementFormatJSONL, Mapper: mapper})))
+		var lines []jsonlStatementLine
+		for scanner.Scan() {
+			var line jsonlStatementLine
+			require.NoError(t, json.Unmarshal(scanner.Bytes(), &line))
+			lines = append(lines, line)
+		}
+		require.Len(t, lines, 5)
+		assert.Equal(t, "INV/posted", lines[2].Reference)
+		assert.Equal(t, "0.50", lines[2].Debit)
+		assert.Equal(t, "11.50", lines[2].BalanceAfter)
+	})
+
+	t.Run("mt940", func(t *testing.T) {
+		got := export(StatementExportOptions{Format: StatementFormatMT940, Mapper: mapper, StatementID: "STMT/2025"})
+		assert.Equal(t, strings.Join([]string{
+			":20:STMT2025",
+			":25:1",
+			":28C:00001",
+			":60F:C700101IDR10,00",
+			":61:7001010101C1,00NTRFINVposted//6e",
+			":86:Top up code 7",
+			":61:7001010101C1,00NTRFINVposted//78",
+			":86:Top up code 7",
+			":61:7001010101D0,50NTRFINVposted//82",
+			":86:Top up code 7",
+			":61:7001010101C1,00NTRFINVposted//8c",
+			":86:Top up code 7",
+			":61:7001010101D0,30NTRFINVpending//96",
+			":86:Top up code 7",
+			":62F:C700101IDR12,20",
+			"-",
+			"",
+		}, "\r\n"), got)
+	})
+
+	t.Run("camt.053", func(t *testing.T) {
+		got := export(StatementExportOptions{
+			Format:    StatementFormatCAMT053,
+			Mapper:    mapper,
+			CreatedAt: time.Date(2025, 8, 24, 0, 0, 0, 0, time.UTC),
+		})
+		var document struct {
+			XMLName xml.Name `xml:"urn:iso:std:iso:20022:tech:xsd:camt.053.001.02 Document"`
+			Stmt    struct {
+				ID       string `xml:"Id"`
+				Balances []struct {
+					Code      string `xml:"Tp>CdOrPrtry>Cd"`
+					Amount    string `xml:"Amt"`
+					Indicator string `xml:"CdtDbtInd"`
+				} `xml:"Bal"`
+				Entries []struct {
+					Amount     camtAmount `xml:"Amt"`
+					Indicator  string     `xml:"CdtDbtInd"`
+					Status     string     `xml:"Sts"`
+					EndToEndID string     `xml:"NtryDtls>TxDtls>Refs>EndToEndId"`
+				} `xml:"Ntry"`
+			} `xml:"BkToCstmrStmt>Stmt"`
+		}
+		require.NoError(t, xml.Unmarshal([]byte(got), &document))
+		assert.Equal(t, "STMT19700101", document.Stmt.ID)
+		require.Len(t, document.Stmt.Balances, 2)
+		assert.Equal(t, "OPBD", document.Stmt.Balances[0].Code)
+		assert.Equal(t, "10.00", document.Stmt.Balances[0].Amount)
+		assert.Equal(t, "12.20", document.Stmt.Balances[1].Amount)
+		assert.Equal(t, "CRDT", document.Stmt.Balances[1].Indicator)
+		require.Len(t, document.Stmt.Entries, 5)
+		assert.Equal(t, "DBIT", document.Stmt.Entries[2].Indicator)
+		assert.Equal(t, camtAmount{Currency: "IDR", Value: "0.50"}, document.Stmt.Entries[2].Amount)
+		assert.Equal(t, "PDNG", document.Stmt.Entries[4].Status)
+		assert.Equal(t, "INV/posted", document.Stmt.Entries[0].EndToEndID)
+	})
+
+	t.Run("reversed", func(t *testing.T) {
+		createdAt := time.Date(2025, 8, 24, 0, 0, 0, 0, time.UTC)
+		for _, format := range []StatementFormat{StatementFormatMT940, StatementFormatCAMT053} {
+			options := StatementExportOptions{Format: format, CreatedAt: createdAt}
+			assert.Equal(t, export(options), exportFilter(statementFakeFilter(2, true), options),
+				"%s entries must be chronological", format)
+		}
+		lines := strings.Split(strings.TrimSpace(exportFilter(statementFakeFilter(2, true),
+			StatementExportOptions{Format: StatementFormatJSONL})), "\n")
+		require.Len(t, lines, 5)
+		assert.Contains(t, lines[0], `"timestamp":50`, "jsonl keeps the filter order")
+	})
+
+	t.Run("long statement id", func(t *testing.T) {
+		got := export(StatementExportOptions{Format: StatementFormatCAMT053, StatementID: strings.Repeat("S", 40)})
+		assert.Equal(t, 2, strings.Count(got, ">"+strings.Repeat("S", 35)+"<"), "MsgId & Stmt/Id")
+		assert.NotContains(t, got, strings.Repeat("S", 36))
+	})
+
+	t.Run("unknown format", func(t *testing.T) {
+		fake := testStatementFixture()
+		_, err := (&Instance{client: &fake}).ExportStatement(&bytes.Buffer{}, statementFakeFilter(2, false),
+			StatementExportOptions{})
+		assert.ErrorIs(t, err, ErrUnknownStatementFormat)
+	})
+}
+
+func TestSwiftText(t *testing.T) {
+	assert.Equal(t, "Pembayaran Rp 1.000 (inv  7)", swiftText("Pembayaran Rp 1.000 (inv #7)", 0, false))
+	assert.Equal(t, "INV7", swiftText("INV / 7", 0, true))
+	assert.Equal(t, "abc", swiftText("abcdef", 3, false))
+}
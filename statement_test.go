@@ -0,0 +1,310 @@
+package tbdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+// statementTestRecords builds records of the account, amounts in IDR minor units.
+type statementTestRecords struct {
+	account, other Uint128
+}
+
+func (r statementTestRecords) amount(v uint64) Amount {
+	return IDR.NewAmountFromMinorUnits(Uint128FromUint64(v))
+}
+
+func (r statementTestRecords) row(ts, debitsPending, debitsPosted, creditsPending, creditsPosted uint64) AccountBalance {
+	return AccountBalance{
+		Timestamp:      ts,
+		DebitsPending:  r.amount(debitsPending),
+		DebitsPosted:   r.amount(debitsPosted),
+		CreditsPending: r.amount(creditsPending),
+		CreditsPosted:  r.amount(creditsPosted),
+	}
+}
+
+func (r statementTestRecords) transfer(id, ts uint64, debit bool, amount uint64, flags TransferFlags) AccountTransfer {
+	transfer := AccountTransfer{
+		ID:              Uint128FromUint64(id),
+		DebitAccountID:  r.other,
+		CreditAccountID: r.account,
+		Amount:          r.amount(amount),
+		Timestamp:       ts,
+		Ledger:          uint32(IDR.EncodeLedger()),
+		Flags:           flags,
+	}
+	if debit {
+		transfer.DebitAccountID, transfer.CreditAccountID = r.account, r.other
+	}
+	return transfer
+}
+
+func (r statementTestRecords) resolve(id, ts uint64, debit bool, amount, pendingID uint64, post bool) AccountTransfer {
+	transfer := r.transfer(id, ts, debit, amount, TransferFlags{PostPendingTransfer: post, VoidPendingTransfer: !post})
+	transfer.PendingID = Uint128FromUint64(pendingID)
+	return transfer
+}
+
+func (r statementTestRecords) filter(reversed bool) AccountTransferFilter {
+	return AccountTransferFilter{
+		AccountID: r.account,
+		Limit:     uint32(TigerBeetleMaxBatch),
+		Monetary:  IDR.NewMonetary(),
+		Flags:     AccountFilterFlags{Debits: true, Credits: true, Reversed: reversed},
+	}
+}
+
+type statementTestEntry struct {
+	kind                  StatementEntryKind
+	debit, credit         uint64
+	before, after         string
+	pendingID, transferID uint64
+}
+
+func assertStatementEntries(t *testing.T, want []statementTestEntry, got []AccountStatement) {
+	t.Helper()
+	require.Len(t, got, len(want))
+	for idx, entry := range got {
+		assert.Equal(t, want[idx].kind, entry.Kind, "entry %d kind", idx)
+		assert.Equal(t, Uint128FromUint64(want[idx].debit), entry.Debit.ToUint128FromValue(), "entry %d debit", idx)
+		assert.Equal(t, Uint128FromUint64(want[idx].credit), entry.Credit.ToUint128FromValue(), "entry %d credit", idx)
+		assert.Equal(t, want[idx].before, entry.SignedBalanceBefore.String(), "entry %d balance before", idx)
+		assert.Equal(t, want[idx].after, entry.SignedBalanceAfter.String(), "entry %d balance after", idx)
+		assert.Equal(t, Uint128FromUint64(want[idx].pendingID), entry.PendingID, "entry %d pending id", idx)
+		assert.Equal(t, Uint128FromUint64(want[idx].transferID), entry.ID, "entry %d id", idx)
+	}
+}
+
+func TestBuildStatementCreditNormal(t *testing.T) {
+	r := statementTestRecords{account: Uint128FromUint64(1), other: Uint128FromUint64(2)}
+	account := Account{ID: r.account, Code: uint16(AccountCategoryBalance), Ledger: uint32(IDR.EncodeLedger())}
+	expiresAt := 40 + uint64(time.Second)
+
+	hold := r.transfer(12, 40, true, 100, TransferFlags{Pending: true})
+	hold.Timeout = 1
+	transfers := []AccountTransfer{
+		r.transfer(10, 10, false, 500, TransferFlags{}),
+		r.transfer(11, 20, true, 200, TransferFlags{Pending: true}),
+		r.resolve(13, 30, true, 150, 11, true),
+		hold,
+		r.transfer(14, expiresAt+10, false, 300, TransferFlags{Pending: true}),
+		r.resolve(15, expiresAt+20, false, 0, 14, false),
+		r.resolve(16, expiresAt+30, true, 0, 9, false),
+	}
+	balances := []AccountBalance{
+		r.row(10, 70, 0, 0, 1500),
+		r.row(20, 270, 0, 0, 1500),
+		r.row(30, 70, 150, 0, 1500),
+		r.row(40, 170, 150, 0, 1500),
+		r.row(expiresAt, 70, 150, 0, 1500),
+		r.row(expiresAt+10, 70, 150, 300, 1500),
+		r.row(expiresAt+20, 70, 150, 0, 1500),
+		r.row(expiresAt+30, 0, 150, 0, 1500),
+	}
+	pendings := map[Uint128]AccountTransfer{
+		Uint128FromUint64(9): r.transfer(9, 1, true, 70, TransferFlags{Pending: true}),
+	}
+	for _, transfer := range transfers {
+		if transfer.Flags.Pending {
+			pendings[transfer.ID] = transfer
+		}
+	}
+
+	src := newStatementSource(account, r.filter(false), balances, transfers)
+	src.pendings = pendings
+	opening := r.row(0, 70, 0, 0, 1000).sides()
+	src.opening = &opening
+	statement := buildStatement(src, func(id, _ Uint128, _ uint64, _ uint32, _ uint16, _ TransferFlags, _ uint64) any {
+		return id.String()
+	})
+
+	assert.Empty(t, statement.Diagnostics)
+	assert.Equal(t, NormalSideCredit, statement.NormalSide)
+	assert.Equal(t, "IDR 9.30", statement.Opening.String())
+	assert.Equal(t, "IDR 13.50", statement.Closing.String())
+	assert.Equal(t, Uint128FromUint64(300), statement.TotalDebits.ToUint128FromValue())
+	assert.Equal(t, Uint128FromUint64(720), statement.TotalCredits.ToUint128FromValue())
+	assertStatementEntries(t, []statementTestEntry{
+		{kind: StatementEntryPosted, credit: 500, before: "IDR 9.30", after: "IDR 14.30", transferID: 10},
+		{kind: StatementEntryPending, debit: 200, before: "IDR 14.30", after: "IDR 12.30", transferID: 11},
+		{kind: StatementEntryPostPending, credit: 50, before: "IDR 12.30", after: "IDR 12.80", pendingID: 11, transferID: 13},
+		{kind: StatementEntryPending, debit: 100, before: "IDR 12.80", after: "IDR 11.80", transferID: 12},
+		{kind: StatementEntryExpired, credit: 100, before: "IDR 11.80", after: "IDR 12.80", pendingID: 12},
+		{kind: StatementEntryPending, before: "IDR 12.80", after: "IDR 12.80", transferID: 14},
+		{kind: StatementEntryVoidPending, before: "IDR 12.80", after: "IDR 12.80", pendingID: 14, transferID: 15},
+		{kind: StatementEntryVoidPending, credit: 70, before: "IDR 12.80", after: "IDR 13.50", pendingID: 9, transferID: 16},
+	}, statement.Entries)
+	assert.Equal(t, Uint128FromUint64(12).String(), statement.Entries[3].Additional)
+	assert.Equal(t, Uint128FromUint64(100), statement.Entries[4].Amount.ToUint128FromValue())
+}
+
+func TestBuildStatementDebitNormal(t *testing.T) {
+	r := statementTestRecords{account: Uint128FromUint64(1), other: Uint128FromUint64(2)}
+	account := Account{ID: r.account, Code: uint16(AccountCategoryControl)}
+	transfers := []AccountTransfer{
+		r.transfer(10, 10, false, 800, TransferFlags{}),
+		r.transfer(11, 20, true, 500, TransferFlags{}),
+		r.transfer(12, 30, false, 100, TransferFlags{Pending: true}),
+	}
+	balances := []AccountBalance{
+		r.row(10, 0, 0, 0, 800),
+		r.row(20, 0, 500, 0, 800),
+		r.row(30, 0, 500, 100, 800),
+	}
+
+	// Not continuous, balances before are derived from the rows.
+	filter := r.filter(false)
+	filter.Code = 1
+	src := newStatementSource(account, filter, balances, transfers)
+	statement := buildStatement(src, nil)
+
+	assert.Empty(t, statement.Diagnostics)
+	assert.Equal(t, NormalSideDebit, statement.NormalSide)
+	assert.Equal(t, "IDR 0.00", statement.Opening.String())
+	assert.Equal(t, "IDR -4.00", statement.Closing.String())
+	assert.True(t, statement.Closing.Negative)
+	assertStatementEntries(t, []statementTestEntry{
+		{kind: StatementEntryPosted, credit: 800, before: "IDR 0.00", after: "IDR -8.00", transferID: 10},
+		{kind: StatementEntryPosted, debit: 500, before: "IDR -8.00", after: "IDR -3.00", transferID: 11},
+		{kind: StatementEntryPending, credit: 100, before: "IDR -3.00", after: "IDR -4.00", transferID: 12},
+	}, statement.Entries)
+}
+
+func TestBuildStatementDiagnostics(t *testing.T) {
+	r := statementTestRecords{account: Uint128FromUint64(1), other: Uint128FromUint64(2)}
+	account := Account{ID: r.account, Code: uint16(AccountCategoryBalance)}
+	transfers := []AccountTransfer{
+		r.transfer(10, 10, false, 500, TransferFlags{}),
+		r.transfer(11, 20, false, 100, TransferFlags{}),
+		r.resolve(12, 40, true, 30, 9, true),
+		r.transfer(13, 60, false, 10, TransferFlags{}),
+	}
+	balances := []AccountBalance{
+		r.row(10, 80, 0, 0, 500),
+		// No balance row of transfer 11.
+		r.row(30, 80, 0, 0, 650),
+		r.row(40, 0, 30, 0, 650),
+		r.row(50, 0, 30, 0, 700),
+		r.row(60, 0, 30, 0, 710),
+	}
+
+	src := newStatementSource(account, r.filter(true), nil, nil)
+	src.balances, src.transfers = balances, transfers
+	src.pendings = map[Uint128]AccountTransfer{}
+	opening := r.row(0, 80, 0, 0, 0).sides()
+	src.opening = &opening
+	statement := buildStatement(src, nil)
+
+	// Entries are kept, in the filter order.
+	require.Len(t, statement.Entries, 4)
+	assert.Equal(t, Uint128FromUint64(13), statement.Entries[0].ID)
+	assert.Equal(t, "IDR -0.80", statement.Opening.String())
+	assert.Equal(t, "IDR 6.80", statement.Closing.String())
+
+	// Pending transfer 9 is derived from the balances: released 80, posted 30.
+	assert.Equal(t, Uint128FromUint64(50), statement.Entries[1].Credit.ToUint128FromValue())
+
+	type diagnostic struct {
+		index int
+		id    uint64
+	}
+	var got []diagnostic
+	for _, d := range statement.Diagnostics {
+		assert.ErrorIs(t, d.Err, ErrStatementUnreconciled)
+		got = append(got, diagnostic{d.Index, d.TransferID.Lo})
+	}
+	assert.Equal(t, []diagnostic{
+		// Balance row at 50 without transfer, then transfer 13 is consistent again.
+		{-1, 0},
+		// Balance at 30 without transfer.
+		{-1, 0},
+		// Transfer 11 without balance row.
+		{2, 11},
+	}, got)
+
+	// A balance row not covering its transfer.
+	src = newStatementSource(account, r.filter(false), []AccountBalance{r.row(10, 0, 0, 0, 100)},
+		[]AccountTransfer{r.transfer(10, 10, false, 500, TransferFlags{})})
+	statement = buildStatement(src, nil)
+	require.Len(t, statement.Entries, 1)
+	require.Len(t, statement.Diagnostics, 1)
+	assert.Equal(t, 0, statement.Diagnostics[0].Index)
+	assert.Equal(t, "IDR 1.00", statement.Entries[0].SignedBalanceAfter.String())
+}
+
+func TestNewStatementSourcePages(t *testing.T) {
+	r := statementTestRecords{account: Uint128FromUint64(1), other: Uint128FromUint64(2)}
+	account := Account{ID: r.account, Code: uint16(AccountCategoryBalance)}
+	filter := r.filter(false)
+	filter.Limit = 2
+
+	// Both complete at 20.
+	transfers := []AccountTransfer{
+		r.transfer(10, 10, false, 500, TransferFlags{}),
+		r.transfer(11, 20, false, 100, TransferFlags{}),
+	}
+	balances := []AccountBalance{r.row(10, 0, 0, 0, 500), r.row(20, 0, 0, 0, 600)}
+	src := newStatementSource(account, filter, balances, transfers)
+	assert.True(t, src.continuous())
+	assert.Equal(t, uint64(20), src.cursor)
+	statement := buildStatement(src, nil)
+	assert.Empty(t, statement.Diagnostics)
+	assert.Len(t, statement.Entries, 2)
+
+	// Balances complete at 15.
+	src = newStatementSource(account, filter, []AccountBalance{r.row(10, 0, 0, 0, 500), r.row(15, 0, 0, 0, 500)},
+		[]AccountTransfer{r.transfer(10, 10, false, 500, TransferFlags{})})
+	assert.Equal(t, uint64(15), src.cursor)
+	statement = buildStatement(src, nil)
+	assert.Len(t, statement.Entries, 1)
+	assert.Len(t, statement.Diagnostics, 1, "balance row without transfer")
+
+	// Transfer 30 is on the next page.
+	src = newStatementSource(account, filter, []AccountBalance{r.row(10, 0, 0, 0, 500), r.row(20, 0, 0, 0, 600)},
+		[]AccountTransfer{r.transfer(10, 10, false, 500, TransferFlags{}), r.transfer(30, 30, false, 1, TransferFlags{})})
+	assert.Equal(t, uint64(20), src.cursor)
+	require.Len(t, src.transfers, 1)
+	statement = buildStatement(src, nil)
+	assert.Len(t, statement.Entries, 1)
+	assert.Len(t, statement.Diagnostics, 1, "balance row without transfer")
+
+	// Last page.
+	filter.Limit = 3
+	src = newStatementSource(account, filter, []AccountBalance{r.row(10, 0, 0, 0, 500)},
+		[]AccountTransfer{r.transfer(10, 10, false, 500, TransferFlags{})})
+	assert.Zero(t, src.cursor)
+
+	// Truncated at the start.
+	filter.Limit = 2
+	filter.Flags.Reversed = true
+	src = newStatementSource(account, filter, []AccountBalance{r.row(20, 0, 0, 0, 600), r.row(10, 0, 0, 0, 500)},
+		[]AccountTransfer{r.transfer(11, 20, false, 100, TransferFlags{})})
+	assert.True(t, src.continuous(), "records are continuous from the cursor")
+	assert.Equal(t, uint64(10), src.cursor)
+	assert.Equal(t, uint64(10), src.balances[0].Timestamp)
+}
+
+func TestGetStatementWithoutHistory(t *testing.T) {
+	client := &fakeClient{accounts: []types.Account{{
+		ID:     toBinding(Uint128FromUint64(1)),
+		Ledger: uint32(IDR.EncodeLedger()),
+		Code:   uint16(AccountCategoryBalance),
+	}}}
+	i := &Instance{client: client}
+	filter := AccountTransferFilter{
+		TimeMin:   time.Unix(0, 1),
+		TimeMax:   time.Unix(0, 10),
+		AccountID: Uint128FromUint64(1),
+		Limit:     10,
+		Flags:     AccountFilterFlags{Debits: true, Credits: true},
+	}
+	_, err := i.GetStatement(filter)
+	assert.ErrorIs(t, err, ErrAccountWithoutHistory)
+	_, err = i.StreamStatement(filter, func(AccountStatement) error { return nil })
+	assert.ErrorIs(t, err, ErrAccountWithoutHistory)
+}
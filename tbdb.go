@@ -3,6 +3,7 @@ package tbdb
 import (
 	"context"
 	"errors"
+	"fmt"
 	"strings"
 	"time"
 
@@ -18,6 +19,10 @@ type Instance struct {
 	// Private field.
 	cfg       *Config
 	startTime time.Time
+	idGen     IDGenerator
+	preflight bool
+	chart     *ChartOfAccounts
+	chartErr  error
 	*instanceGen
 }
 
@@ -28,6 +33,10 @@ func New() *Instance {
 		cfg:         config,
 		instanceGen: &instanceGen{priority: config.DependencyPriority},
 	}
+	if config.ChartOfAccounts != "" {
+		// Returned by Open, so an invalid chart fails the startup.
+		instance.chart, instance.chartErr = LoadChartOfAccounts(config.ChartOfAccounts)
+	}
 	return instance
 }
 
@@ -58,6 +67,10 @@ func (i *Instance) HealthCheck(ctx context.Context) *qore.DependencyStats {
 
 // Open an backend connection or construct the dependency.
 func (i *Instance) Open() error {
+	if i.chartErr != nil {
+		return fmt.Errorf("load chart of accounts: %w", i.chartErr)
+	}
+
 	// Setup addresses.
 	var addrs []string
 	for addr := range strings.SplitSeq(i.cfg.Addresses, ",") {
@@ -76,6 +89,13 @@ func (i *Instance) Open() error {
 	// Set another instance field.
 	i.startTime = time.Now()
 
+	// Provision the system accounts.
+	if i.chart != nil {
+		if _, err := i.ProvisionChartOfAccounts(i.chart); err != nil {
+			return fmt.Errorf("provision chart of accounts: %w", err)
+		}
+	}
+
 	// Return.
 	return nil
 }
@@ -0,0 +1,394 @@
+package tbdb
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+// TenantID identifies a white-label tenant sharing the cluster, 1-MaxTenantID.
+type TenantID uint16
+
+// Tenant ledger layout: tenantLedgerBase + tenant×100000 + numeric code×100 + decimal.
+// The base is above the Base36LedgerEncoder range, so untenanted ledgers are never taken for tenant ledgers.
+const (
+	tenantLedgerBase  = 2_240_000_000
+	tenantLedgerRange = 100_000
+
+	// MaxTenantID is the largest tenant that fits in the ledger.
+	MaxTenantID TenantID = 20_548
+)
+
+// Compile-time check if TenantLedger implements Ledger interface.
+var _ Ledger = TenantLedger{}
+
+// TenantLedger is a ledger partitioned by tenant, so accounts and transfers of different tenants never share a
+// ledger and TigerBeetle rejects any transfer across tenants.
+// Format: 2,240,000,000 + [tenant × 100,000] + [3-digit ISO 4217 numeric code × 100] + [2-digit decimal]
+//
+// Tenant ledgers are above the HashLedgerEncoder & Base36LedgerEncoder ranges. The currency must have a numeric code,
+// so the built-in USDT, BTC, BNB & ETH can't be tenant ledgers; define custom tokens with an unused NumericCode,
+// see CurrencyDefinition.
+type TenantLedger struct {
+	// Tenant is the ledger owner; required.
+	Tenant TenantID
+	// Currency is the ledger currency; required.
+	Currency *Currency
+}
+
+// Encode generates the LedgerCode of the tenant & currency with validation.
+func (l TenantLedger) Encode() (LedgerCode, error) {
+	switch {
+	case l.Tenant == 0 || l.Tenant > MaxTenantID:
+		return 0, fmt.Errorf("%w: %d", ErrInvalidTenant, l.Tenant)
+	case l.Currency == nil:
+		return 0, ErrLedgerMustNotBeNil
+	case l.Currency.numericCode == 0 || l.Currency.numericCode > 999:
+		return 0, fmt.Errorf("%w: %s needs a numeric code 1-999", ErrInvalidCurrencyCode, l.Currency.code)
+	}
+	code := tenantLedgerBase + uint32(l.Tenant)*tenantLedgerRange + uint32(l.Currency.numericCode)*100 +
+		uint32(l.Currency.decimal)
+	return LedgerCode(code), nil
+}
+
+// EncodeLedger implements Ledger. Returns zero, which TigerBeetle rejects, if the ledger is invalid.
+func (l TenantLedger) EncodeLedger() LedgerCode {
+	code, _ := l.Encode()
+	return code
+}
+
+// DecodeLedger implements Ledger.
+// Returns: "tenant=<tenant>, decimal=<precision>, currency=<code>"
+func (l TenantLedger) DecodeLedger() string {
+	var (
+		code    string
+		decimal uint8
+	)
+	if l.Currency != nil {
+		code, decimal = l.Currency.code, l.Currency.decimal
+	}
+	return fmt.Sprintf("tenant=%d, decimal=%d, currency=%s", l.Tenant, decimal, code)
+}
+
+// ParseTenantLedger decodes a raw TigerBeetle ledger into tenant & registered currency.
+func ParseTenantLedger(ledger uint32) (TenantLedger, error) {
+	tenant, ok := ledgerTenant(ledger)
+	if !ok {
+		return TenantLedger{}, fmt.Errorf("%w: %d is not a tenant ledger", ErrUnknownLedgerCode, ledger)
+	}
+	rest := (ledger - tenantLedgerBase) % tenantLedgerRange
+	numeric, decimal := uint16(rest/100), uint8(rest%100)
+	if cur, ok := lookupNumericCode(numeric); ok && cur.decimal == decimal {
+		return TenantLedger{Tenant: tenant, Currency: cur}, nil
+	}
+	return TenantLedger{}, fmt.Errorf("%w: %d has unregistered numeric code %03d", ErrUnknownLedgerCode, ledger, numeric)
+}
+
+// ledgerTenant returns the tenant of a raw ledger, false if it is not a tenant ledger.
+func ledgerTenant(ledger uint32) (TenantID, bool) {
+	if ledger < tenantLedgerBase {
+		return 0, false
+	}
+	tenant := (ledger - tenantLedgerBase) / tenantLedgerRange
+	if tenant == 0 || tenant > uint32(MaxTenantID) {
+		return 0, false
+	}
+	return TenantID(tenant), true
+}
+
+// TenantInstance is a tenant-scoped view of Instance. It stamps the tenant on every account and transfer ledger,
+// and checks that every account, transfer and query result belongs to the tenant.
+type TenantInstance struct {
+	instance *Instance
+	tenant   TenantID
+}
+
+// Tenant returns the tenant-scoped view of the instance.
+//
+//	acme, err := instance.Tenant(42)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	results, err := acme.CreateAccountsWithCategory(tbdb.AccountCategoryBalance, tbdb.CreateAccount{Ledger: tbdb.IDR})
+func (i *Instance) Tenant(tenant TenantID) (*TenantInstance, error) {
+	if tenant == 0 || tenant > MaxTenantID {
+		return nil, fmt.Errorf("%w: %d", ErrInvalidTenant, tenant)
+	}
+	return &TenantInstance{instance: i, tenant: tenant}, nil
+}
+
+// TenantID returns the tenant of the view.
+func (t *TenantInstance) TenantID() TenantID { return t.tenant }
+
+// stamp returns the tenant ledger of the given currency or tenant ledger.
+func (t *TenantInstance) stamp(ledger Ledger) (Ledger, error) {
+	var stamped TenantLedger
+	switch l := ledger.(type) {
+	case nil:
+		return nil, ErrLedgerMustNotBeNil
+	case *Currency:
+		stamped = TenantLedger{Tenant: t.tenant, Currency: l}
+	case TenantLedger:
+		if l.Tenant != t.tenant {
+			return nil, fmt.Errorf("%w: ledger of tenant %d", ErrTenantMismatch, l.Tenant)
+		}
+		stamped = l
+	default:
+		return nil, fmt.Errorf("%w: ledger %T is not tenant-scoped", ErrTenantMismatch, ledger)
+	}
+	if _, err := stamped.Encode(); err != nil {
+		return nil, err
+	}
+	return stamped, nil
+}
+
+// check returns ErrTenantMismatch if the raw ledger does not belong to the tenant.
+func (t *TenantInstance) check(ledger uint32) error {
+	if tenant, ok := ledgerTenant(ledger); !ok || tenant != t.tenant {
+		return fmt.Errorf("%w: ledger %d", ErrTenantMismatch, ledger)
+	}
+	return nil
+}
+
+// checkAccount returns ErrTenantMismatch if the account does not belong to the tenant.
+func (t *TenantInstance) checkAccount(accountID Uint128) error {
+	cln, err := t.instance.Client()
+	if err != nil {
+		return err
+	}
+	accounts, err := cln.LookupAccounts([]types.Uint128{toBinding(accountID)})
+	if err != nil {
+		return err
+	}
+	if len(accounts) == 0 {
+		return fmt.Errorf("%w: %s", ErrAccountNotFound, accountID.String())
+	}
+	return t.check(accounts[0].Ledger)
+}
+
+// CreateAccountBatch stamps the tenant on each account ledger, then calls Instance.CreateAccountBatch.
+func (t *TenantInstance) CreateAccountBatch(accounts []CreateAccounts) (AccountEventResults, error) {
+	stamped := make([]CreateAccounts, len(accounts))
+	for idx, account := range accounts {
+		ledger, err := t.stamp(account.Ledger)
+		if err != nil {
+			return AccountEventResults{}, fmt.Errorf("account %d: %w", idx, err)
+		}
+		stamped[idx] = account
+		stamped[idx].Ledger = ledger
+	}
+	return t.instance.CreateAccountBatch(stamped)
+}
+
+// CreateAccount stamps the tenant on the account ledger, then calls Instance.CreateAccount.
+func (t *TenantInstance) CreateAccount(account CreateAccount, code uint16, flags AccountFlags) (AccountEventResult, error) {
+	ledger, err := t.stamp(account.Ledger)
+	if err != nil {
+		return AccountEventResult{}, err
+	}
+	account.Ledger = ledger
+	return t.instance.CreateAccount(account, code, flags)
+}
+
+// CreateAccountsWithCategory stamps the tenant on each account ledger, then calls Instance.CreateAccountsWithCategory.
+func (t *TenantInstance) CreateAccountsWithCategory(category AccountCategory, accounts ...CreateAccount) (AccountEventResults, error) {
+	stamped := make([]CreateAccount, len(accounts))
+	for idx, account := range accounts {
+		ledger, err := t.stamp(account.Ledger)
+		if err != nil {
+			return AccountEventResults{}, fmt.Errorf("account %d: %w", idx, err)
+		}
+		stamped[idx] = account
+		stamped[idx].Ledger = ledger
+	}
+	return t.instance.CreateAccountsWithCategory(category, stamped...)
+}
+
+// stampTransfers returns a copy of the transfers with the tenant stamped on each ledger.
+func (t *TenantInstance) stampTransfers(transfers []TransferData) ([]TransferData, error) {
+	stamped := make([]TransferData, len(transfers))
+	for idx, transfer := range transfers {
+		ledger, err := t.stamp(transfer.Ledger)
+		if err != nil {
+			return nil, fmt.Errorf("transfer %d: %w", idx, err)
+		}
+		stamped[idx] = transfer
+		stamped[idx].Ledger = ledger
+	}
+	return stamped, nil
+}
+
+// CreateTransfers stamps the tenant on each transfer ledger, then calls Instance.CreateTransfers.
+// TigerBeetle rejects transfers whose accounts are on another ledger, so they can not cross tenants.
+func (t *TenantInstance) CreateTransfers(transfers []TransferData) (TransferEventResults, error) {
+	stamped, err := t.stampTransfers(transfers)
+	if err != nil {
+		return TransferEventResults{}, err
+	}
+	return t.instance.CreateTransfers(stamped)
+}
+
+// CreatePendingTransfers stamps the tenant on each transfer ledger, then calls Instance.CreatePendingTransfers.
+func (t *TenantInstance) CreatePendingTransfers(
+	transfers []TransferData,
+	linked bool,
+	code uint16,
+) (TransferEventResults, error) {
+	stamped, err := t.stampTransfers(transfers)
+	if err != nil {
+		return TransferEventResults{}, err
+	}
+	return t.instance.CreatePendingTransfers(stamped, linked, code)
+}
+
+// ResolvePendingTransfers checks the pending transfers belong to the tenant, then calls
+// Instance.ResolvePendingTransfers.
+func (t *TenantInstance) ResolvePendingTransfers(pendings []PendingTransfer) (TransferEventResults, error) {
+	cln, err := t.instance.Client()
+	if err != nil {
+		return TransferEventResults{}, err
+	}
+	ids := make([]types.Uint128, 0, len(pendings))
+	for _, pending := range pendings {
+		ids = append(ids, toBinding(pending.PendingID))
+	}
+	found, err := cln.LookupTransfers(ids)
+	if err != nil {
+		return TransferEventResults{}, err
+	}
+	ledgers := make(map[Uint128]uint32, len(found))
+	for _, transfer := range found {
+		ledgers[fromBinding(transfer.ID)] = transfer.Ledger
+	}
+	for _, pending := range pendings {
+		ledger, ok := ledgers[pending.PendingID]
+		if !ok {
+			// Let TigerBeetle report the missing pending transfer.
+			continue
+		}
+		if err := t.check(ledger); err != nil {
+			return TransferEventResults{}, fmt.Errorf("pending transfer %s: %w", pending.PendingID.String(), err)
+		}
+	}
+	return t.instance.ResolvePendingTransfers(pendings)
+}
+
+// LookupAccountsOrdered calls Instance.LookupAccountsOrdered and reports accounts of another tenant as not found,
+// with ErrTenantMismatch.
+func (t *TenantInstance) LookupAccountsOrdered(lookups []AccountLookup) ([]AccountLookupResult, error) {
+	results, err := t.instance.LookupAccountsOrdered(lookups)
+	if err != nil {
+		return nil, err
+	}
+	for idx, result := range results {
+		if !result.Found || result.Err != nil {
+			continue
+		}
+		if err := t.check(result.Account.Ledger); err != nil {
+			// Accounts of another tenant are not exposed.
+			results[idx].Found, results[idx].Account, results[idx].Err = false, Account{}, err
+		}
+	}
+	return results, nil
+}
+
+// LookupAccounts calls Instance.LookupAccounts and returns ErrTenantMismatch if any account belongs to
+// another tenant.
+func (t *TenantInstance) LookupAccounts(lookups []AccountLookup) ([]Account, error) {
+	results, err := t.LookupAccountsOrdered(lookups)
+	if err != nil {
+		return nil, err
+	}
+	return foundAccounts(results)
+}
+
+// LookupAccountsMap calls Instance.LookupAccountsMap and returns ErrTenantMismatch if any account belongs to
+// another tenant.
+func (t *TenantInstance) LookupAccountsMap(lookups []AccountLookup) (map[Uint128]Account, error) {
+	results, err := t.LookupAccountsOrdered(lookups)
+	if err != nil {
+		return nil, err
+	}
+	return accountsByID(results)
+}
+
+// GetHisotricalBalances checks the filter account belongs to the tenant, then calls Instance.GetHisotricalBalances.
+func (t *TenantInstance) GetHisotricalBalances(filter AccountTransferFilter) ([]AccountBalance, error) {
+	if err := t.checkAccount(filter.AccountID); err != nil {
+		return nil, err
+	}
+	return t.instance.GetHisotricalBalances(filter)
+}
+
+// GetAccountTransfers checks the filter account belongs to the tenant, then calls Instance.GetAccountTransfers.
+func (t *TenantInstance) GetAccountTransfers(filter AccountTransferFilter) ([]AccountTransfer, error) {
+	if err := t.checkAccount(filter.AccountID); err != nil {
+		return nil, err
+	}
+	return t.instance.GetAccountTransfers(filter)
+}
+
+// GetAccountStatements checks the filter account belongs to the tenant, then calls Instance.GetAccountStatements.
+func (t *TenantInstance) GetAccountStatements(
+	filter AccountTransferFilter,
+	closureFn ...StatementClosureFn,
+) ([]AccountStatement, error) {
+	if err := t.checkAccount(filter.AccountID); err != nil {
+		return nil, err
+	}
+	return t.instance.GetAccountStatements(filter, closureFn...)
+}
+
+// GetStatement checks the filter account belongs to the tenant, then calls Instance.GetStatement.
+func (t *TenantInstance) GetStatement(filter AccountTransferFilter, closureFn ...StatementClosureFn) (*Statement, error) {
+	if err := t.checkAccount(filter.AccountID); err != nil {
+		return nil, err
+	}
+	return t.instance.GetStatement(filter, closureFn...)
+}
+
+// StreamStatement checks the filter account belongs to the tenant, then calls Instance.StreamStatement.
+func (t *TenantInstance) StreamStatement(
+	filter AccountTransferFilter,
+	fn func(entry AccountStatement) error,
+	closureFn ...StatementClosureFn,
+) (*Statement, error) {
+	if err := t.checkAccount(filter.AccountID); err != nil {
+		return nil, err
+	}
+	return t.instance.StreamStatement(filter, fn, closureFn...)
+}
+
+// ExportStatement checks the filter account belongs to the tenant, then calls Instance.ExportStatement.
+func (t *TenantInstance) ExportStatement(
+	w io.Writer,
+	filter AccountTransferFilter,
+	options StatementExportOptions,
+) (*Statement, error) {
+	if err := t.checkAccount(filter.AccountID); err != nil {
+		return nil, err
+	}
+	return t.instance.ExportStatement(w, filter, options)
+}
+
+// GetBalanceSeries checks the filter account belongs to the tenant, then calls Instance.GetBalanceSeries.
+func (t *TenantInstance) GetBalanceSeries(filter BalanceSeriesFilter) ([]BalancePeriod, error) {
+	if err := t.checkAccount(filter.AccountID); err != nil {
+		return nil, err
+	}
+	return t.instance.GetBalanceSeries(filter)
+}
+
+// GetTrialBalance stamps the tenant on the filter ledger, then calls Instance.GetTrialBalance with the accounts
+// of the tenant: account ids of another tenant return ErrTenantMismatch, queried accounts of another tenant are skipped.
+func (t *TenantInstance) GetTrialBalance(filter TrialBalanceFilter) (*TrialBalance, error) {
+	if filter.Ledger != nil {
+		ledger, err := t.stamp(filter.Ledger)
+		if err != nil {
+			return nil, err
+		}
+		filter.Ledger = ledger
+	}
+	return t.instance.trialBalance(filter, t.check)
+}
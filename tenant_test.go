@@ -0,0 +1,177 @@
+package tbdb
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+func TestTenantLedgerEncode(t *testing.T) {
+	tests := []struct {
+		name    string
+		ledger  TenantLedger
+		want    LedgerCode
+		wantErr error
+	}{
+		{"IDR", TenantLedger{Tenant: 1, Currency: IDR}, 2_240_136_002, nil},
+		{"USD", TenantLedger{Tenant: 42, Currency: USD}, 2_244_284_002, nil},
+		{"max tenant", TenantLedger{Tenant: MaxTenantID, Currency: EUR}, 4_294_897_802, nil},
+		{"zero tenant", TenantLedger{Currency: IDR}, 0, ErrInvalidTenant},
+		{"tenant overflow", TenantLedger{Tenant: MaxTenantID + 1, Currency: IDR}, 0, ErrInvalidTenant},
+		{"nil currency", TenantLedger{Tenant: 1}, 0, ErrLedgerMustNotBeNil},
+		{"no numeric code", TenantLedger{Tenant: 1, Currency: USDT}, 0, ErrInvalidCurrencyCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.ledger.Encode()
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr, "Encode must return error")
+				assert.Zero(t, tt.ledger.EncodeLedger(), "EncodeLedger must return zero for invalid ledger")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got, "Encode returned unexpected result")
+			assert.Equal(t, tt.want, tt.ledger.EncodeLedger(), "EncodeLedger returned unexpected result")
+		})
+	}
+	assert.Equal(t, "tenant=7, decimal=2, currency=IDR", TenantLedger{Tenant: 7, Currency: IDR}.DecodeLedger())
+}
+
+func TestTenantLedgerRange(t *testing.T) {
+	ledger, err := Base36LedgerEncoder{}.EncodeLedger("ZZZZZZ", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, base36MaxLedger, ledger)
+	_, ok := ledgerTenant(uint32(ledger))
+	assert.False(t, ok, "base36 ledger must not be a tenant ledger")
+
+	// Lowest & highest tenant ledgers.
+	assert.Greater(t, uint32(tenantLedgerBase+tenantLedgerRange), uint32(base36MaxLedger))
+	highest := uint64(tenantLedgerBase) + uint64(MaxTenantID)*tenantLedgerRange + tenantLedgerRange - 1
+	assert.LessOrEqual(t, highest, uint64(math.MaxUint32))
+	assert.Greater(t, highest+tenantLedgerRange, uint64(math.MaxUint32), "MaxTenantID must be the largest")
+}
+
+func TestParseTenantLedger(t *testing.T) {
+	jpy, ok := LookupCurrency("JPY")
+	assert.True(t, ok)
+	token := newCurrencyFromDefinition(CurrencyDefinition{Code: "TNT", Decimal: 4, NumericCode: 995})
+	assert.NoError(t, RegisterCurrency(token))
+
+	tests := []struct {
+		name    string
+		ledger  uint32
+		want    TenantLedger
+		wantErr error
+	}{
+		{"IDR", 2_240_136_002, TenantLedger{Tenant: 1, Currency: IDR}, nil},
+		{"JPY", 2_244_239_200, TenantLedger{Tenant: 42, Currency: jpy}, nil},
+		{"custom token", 2_240_299_504, TenantLedger{Tenant: 2, Currency: token}, nil},
+		{"below range", uint32(IDR.EncodeLedger()), TenantLedger{}, ErrUnknownLedgerCode},
+		{"base36 range", uint32(base36MaxLedger), TenantLedger{}, ErrUnknownLedgerCode},
+		{"zero tenant", 2_240_036_002, TenantLedger{}, ErrUnknownLedgerCode},
+		{"unknown numeric code", 2_240_199_902, TenantLedger{}, ErrUnknownLedgerCode},
+		{"decimal mismatch", 2_240_136_008, TenantLedger{}, ErrUnknownLedgerCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTenantLedger(tt.ledger)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr, "ParseTenantLedger(%d) must return error", tt.ledger)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got, "ParseTenantLedger(%d) returned unexpected result", tt.ledger)
+		})
+	}
+
+	cur, err := ParseLedgerCode(2_240_136_002)
+	assert.NoError(t, err)
+	assert.Equal(t, IDR, cur, "ParseLedgerCode returned unexpected result for tenant ledger")
+}
+
+func TestTenantInstanceStamp(t *testing.T) {
+	_, err := (&Instance{}).Tenant(0)
+	assert.ErrorIs(t, err, ErrInvalidTenant)
+
+	tenant, err := (&Instance{}).Tenant(42)
+	assert.NoError(t, err)
+	assert.Equal(t, TenantID(42), tenant.TenantID())
+
+	tests := []struct {
+		name    string
+		ledger  Ledger
+		want    Ledger
+		wantErr error
+	}{
+		{"currency", IDR, TenantLedger{Tenant: 42, Currency: IDR}, nil},
+		{"same tenant", TenantLedger{Tenant: 42, Currency: USD}, TenantLedger{Tenant: 42, Currency: USD}, nil},
+		{"other tenant", TenantLedger{Tenant: 7, Currency: USD}, nil, ErrTenantMismatch},
+		{"nil", nil, nil, ErrLedgerMustNotBeNil},
+		{"no numeric code", USDT, nil, ErrInvalidCurrencyCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tenant.stamp(tt.ledger)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr, "stamp must return error")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got, "stamp returned unexpected result")
+		})
+	}
+
+	// Caller slices are left untouched.
+	transfers := []TransferData{{Ledger: IDR}}
+	stamped, err := tenant.stampTransfers(transfers)
+	assert.NoError(t, err)
+	assert.Equal(t, IDR, transfers[0].Ledger)
+	assert.Equal(t, TenantLedger{Tenant: 42, Currency: IDR}, stamped[0].Ledger)
+}
+
+func TestTenantInstanceCheck(t *testing.T) {
+	own := TenantLedger{Tenant: 42, Currency: IDR}
+	other := TenantLedger{Tenant: 7, Currency: IDR}
+	client := &fakeClient{
+		accounts: []types.Account{
+			{ID: types.ToUint128(1), Ledger: uint32(own.EncodeLedger())},
+			{ID: types.ToUint128(2), Ledger: uint32(other.EncodeLedger())},
+		},
+		transfers: []types.Transfer{
+			{ID: types.ToUint128(10), Ledger: uint32(other.EncodeLedger())},
+		},
+	}
+	tenant, err := (&Instance{client: client}).Tenant(42)
+	assert.NoError(t, err)
+
+	accounts, err := tenant.LookupAccounts([]AccountLookup{{ID: Uint128FromUint64(1)}})
+	assert.NoError(t, err)
+	assert.Len(t, accounts, 1)
+
+	_, err = tenant.LookupAccounts([]AccountLookup{{ID: Uint128FromUint64(1)}, {ID: Uint128FromUint64(2)}})
+	assert.ErrorIs(t, err, ErrTenantMismatch, "LookupAccounts must reject accounts of other tenant")
+
+	results, err := tenant.LookupAccountsOrdered([]AccountLookup{{ID: Uint128FromUint64(2)}, {ID: Uint128FromUint64(3)}})
+	assert.NoError(t, err)
+	assert.False(t, results[0].Found, "LookupAccountsOrdered must not expose accounts of other tenant")
+	assert.Equal(t, Account{}, results[0].Account)
+	assert.ErrorIs(t, results[0].Err, ErrTenantMismatch)
+	assert.False(t, results[1].Found)
+
+	_, err = tenant.GetAccountTransfers(AccountTransferFilter{AccountID: Uint128FromUint64(2)})
+	assert.ErrorIs(t, err, ErrTenantMismatch, "GetAccountTransfers must reject accounts of other tenant")
+
+	_, err = tenant.GetAccountStatements(AccountTransferFilter{AccountID: Uint128FromUint64(3)})
+	assert.ErrorIs(t, err, ErrAccountNotFound, "GetAccountStatements must reject unknown accounts")
+
+	_, err = tenant.ResolvePendingTransfers([]PendingTransfer{{PendingID: Uint128FromUint64(10)}})
+	assert.ErrorIs(t, err, ErrTenantMismatch, "ResolvePendingTransfers must reject transfers of other tenant")
+
+	_, err = tenant.CreateTransfers([]TransferData{{Ledger: other}})
+	assert.ErrorIs(t, err, ErrTenantMismatch, "CreateTransfers must reject ledger of other tenant")
+}
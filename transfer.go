@@ -74,28 +74,45 @@ func (i *Instance) doTransfers(transfers []TransferData) (TransferEventResults,
 		return TransferEventResults{}, err
 	}
 
-	// Convert TransferData structs to TigerBeetle's native Transfer format.
-	tbTransfers := make([]types.Transfer, 0, countTransfer)
+	// Generate IDs of transfers with zero ID.
 	generatedIDs := make([]Uint128, countTransfer)
 	for idx, transfer := range transfers {
-		var id Uint128
 		if transfer.ID.IsZero() {
-			id = NewID()
+			generatedIDs[idx] = i.newID()
 		} else {
-			id = transfer.ID
+			generatedIDs[idx] = transfer.ID
+		}
+	}
+
+	// Preflight validation, rejected transfers are not sent.
+	var failures map[int]preflightFailure
+	if i.preflight {
+		failures = preflightTransfers(transfers, generatedIDs)
+	}
+
+	// Convert TransferData structs to TigerBeetle's native Transfer format.
+	tbTransfers := make([]types.Transfer, 0, countTransfer-len(failures))
+	sentIndexes := make([]int, 0, countTransfer-len(failures))
+	for idx, transfer := range transfers {
+		if _, failed := failures[idx]; failed {
+			continue
 		}
-		generatedIDs[idx] = id
 
 		var ledger uint32
 		if transfer.Ledger != nil {
 			ledger = uint32(transfer.Ledger.EncodeLedger())
 		}
+		var amount Uint128
+		if transfer.Amount != nil {
+			amount = transfer.Amount.ToUint128FromValue()
+		}
 
+		sentIndexes = append(sentIndexes, idx)
 		tbTransfers = append(tbTransfers, types.Transfer{
-			ID:              toBinding(id),
+			ID:              toBinding(generatedIDs[idx]),
 			DebitAccountID:  toBinding(transfer.DebitAccountID),
 			CreditAccountID: toBinding(transfer.CreditAccountID),
-			Amount:          toBinding(transfer.Amount.ToUint128FromValue()),
+			Amount:          toBinding(amount),
 			PendingID:       toBinding(transfer.pendingID),
 			UserData128:     toBinding(transfer.UserData128),
 			UserData64:      transfer.UserData64,
@@ -109,9 +126,11 @@ func (i *Instance) doTransfers(transfers []TransferData) (TransferEventResults,
 	}
 
 	// Execute batch transfer creation.
-	tbResults, err := cln.CreateTransfers(tbTransfers)
-	if err != nil {
-		return TransferEventResults{}, err
+	var tbResults []types.TransferEventResult
+	if len(tbTransfers) > 0 {
+		if tbResults, err = cln.CreateTransfers(tbTransfers); err != nil {
+			return TransferEventResults{}, err
+		}
 	}
 	result := TransferEventResults{
 		SuccessCount: countTransfer,
@@ -128,10 +147,19 @@ func (i *Instance) doTransfers(transfers []TransferData) (TransferEventResults,
 		}
 	}
 
+	// Update the transfers rejected by preflight.
+	for idx, failure := range failures {
+		result.SuccessCount--
+		result.FailedCount++
+		result.Results[idx].Result = failure.result
+		result.Results[idx].Err = failure.err
+	}
+
 	// Update only the failed transfers from TigerBeetle results.
-	// TigerBeetle only returns results for failed transfers.
+	// TigerBeetle only returns results for failed transfers, indexed by the sent transfers.
 	for _, tbResult := range tbResults {
-		if tbResult.Index < uint32(countTransfer) {
+		if tbResult.Index < uint32(len(sentIndexes)) {
+			index := sentIndexes[tbResult.Index]
 			var resultErr error
 			var errCount int
 			if tbResult.Result != types.TransferOK {
@@ -141,9 +169,9 @@ func (i *Instance) doTransfers(transfers []TransferData) (TransferEventResults,
 
 			result.SuccessCount = result.SuccessCount - errCount
 			result.FailedCount = result.FailedCount + errCount
-			result.Results[tbResult.Index] = TransferEventResult{
-				Index:  tbResult.Index,
-				ID:     generatedIDs[tbResult.Index],
+			result.Results[index] = TransferEventResult{
+				Index:  uint32(index),
+				ID:     generatedIDs[index],
 				Result: tbResult.Result,
 				Err:    resultErr,
 			}
@@ -0,0 +1,251 @@
+package tbdb
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+// TransferPreflightZeroAmount is the result of a non pending transfer that moves no amount, its error wraps
+// ErrZeroAmount. It is not a TigerBeetle result code: the cluster accepts zero amounts, preflight reports them
+// since they are mistakes in practice.
+const TransferPreflightZeroAmount CreateTransferResult = math.MaxUint32
+
+// maxUint128 is 2^128 - 1, the reserved TigerBeetle ID.
+var maxUint128 = Uint128{Hi: ^uint64(0), Lo: ^uint64(0)}
+
+// SetTransferPreflight enables or disables the preflight validation of transfer creation.
+// When enabled, transfers rejected by PreflightTransfers are reported without being sent,
+// and only the remaining transfers go to the cluster. Set it before the instance is shared across goroutines.
+func (i *Instance) SetTransferPreflight(enabled bool) { i.preflight = enabled }
+
+// PreflightTransfers validates transfers locally, without a network round trip, and reports them in the same
+// shape and with the same result codes as TigerBeetle. Transfers with zero ID are treated as unique, since
+// their ID is generated on creation.
+//
+// Checked are reserved IDs, duplicate IDs within the batch, flag combinations, pending ID & timeout usage,
+// zero or identical accounts, zero ledger & code, an Amount in another currency than the Ledger
+// (TransferTransferMustHaveTheSameLedgerAsAccounts wrapping ErrCurrencyAmountMismatch), zero amount of
+// non pending transfers (TransferPreflightZeroAmount wrapping ErrZeroAmount) and linked chains, a failed event fails its whole chain.
+func PreflightTransfers(transfers []TransferData) TransferEventResults {
+	ids := make([]Uint128, len(transfers))
+	for idx, transfer := range transfers {
+		ids[idx] = transfer.ID
+	}
+	failures := preflightTransfers(transfers, ids)
+
+	result := TransferEventResults{
+		SuccessCount: len(transfers) - len(failures),
+		FailedCount:  len(failures),
+		Results:      make([]TransferEventResult, len(transfers)),
+	}
+	for idx := range transfers {
+		result.Results[idx] = TransferEventResult{Index: uint32(idx), ID: ids[idx], Result: types.TransferOK}
+	}
+	for idx, failure := range failures {
+		result.Results[idx].Result = failure.result
+		result.Results[idx].Err = failure.err
+	}
+	return result
+}
+
+// preflightFailure defines a transfer rejected by preflight.
+type preflightFailure struct {
+	result CreateTransferResult
+	err    error
+}
+
+// newPreflightFailure creates the failure of the result, wrapping the optional cause.
+func newPreflightFailure(result CreateTransferResult, cause error) preflightFailure {
+	name := result.String()
+	if result == TransferPreflightZeroAmount {
+		name, cause = "TransferPreflightZeroAmount", ErrZeroAmount
+	}
+	if cause != nil {
+		return preflightFailure{result, fmt.Errorf("%w: %s: %w", ErrTransferPreflight, name, cause)}
+	}
+	return preflightFailure{result, fmt.Errorf("%w: %s", ErrTransferPreflight, name)}
+}
+
+// preflightTransfers validates transfers with their effective IDs, processing the batch in order like
+// TigerBeetle does. Returns the failures by transfer index.
+func preflightTransfers(transfers []TransferData, ids []Uint128) map[int]preflightFailure {
+	failures := make(map[int]preflightFailure)
+	created := make(map[Uint128]int, len(transfers))
+	chainStart, chainFailed := -1, false
+	for idx, transfer := range transfers {
+		if chainStart < 0 && transfer.flags.Linked {
+			chainStart, chainFailed = idx, false
+		}
+
+		var failure preflightFailure
+		switch {
+		case chainStart >= 0 && chainFailed:
+			failure = newPreflightFailure(types.TransferLinkedEventFailed, nil)
+		case transfer.flags.Linked && idx == len(transfers)-1:
+			failure = newPreflightFailure(types.TransferLinkedEventChainOpen, nil)
+		default:
+			failure = preflightTransfer(transfer, ids[idx])
+			if first, exists := created[ids[idx]]; exists && failure.result == types.TransferOK {
+				failure = newPreflightFailure(compareTransfer(transfers[first], transfer), nil)
+			}
+		}
+
+		if failure.result == types.TransferOK {
+			if !ids[idx].IsZero() {
+				created[ids[idx]] = idx
+			}
+		} else {
+			failures[idx] = failure
+			// The chain is rolled back, so the preceding events fail too.
+			if chainStart >= 0 && !chainFailed {
+				chainFailed = true
+				for prev := chainStart; prev < idx; prev++ {
+					failures[prev] = newPreflightFailure(types.TransferLinkedEventFailed, nil)
+					if created[ids[prev]] == prev {
+						delete(created, ids[prev])
+					}
+				}
+			}
+		}
+
+		if chainStart >= 0 && !transfer.flags.Linked {
+			chainStart = -1
+		}
+	}
+	return failures
+}
+
+// preflightTransfer validates a single transfer in the TigerBeetle order of checks.
+func preflightTransfer(t TransferData, id Uint128) preflightFailure {
+	flags := t.flags
+	postOrVoid := flags.PostPendingTransfer || flags.VoidPendingTransfer
+	var amount Uint128
+	if t.Amount != nil {
+		amount = t.Amount.ToUint128FromValue()
+	}
+
+	switch {
+	case t.timestamp != 0 && !flags.Imported:
+		return newPreflightFailure(types.TransferTimestampMustBeZero, nil)
+	case id == maxUint128:
+		return newPreflightFailure(types.TransferIDMustNotBeIntMax, nil)
+	case flags.Pending && postOrVoid, flags.PostPendingTransfer && flags.VoidPendingTransfer:
+		return newPreflightFailure(types.TransferFlagsAreMutuallyExclusive, nil)
+	case postOrVoid && (flags.BalancingDebit || flags.BalancingCredit || flags.ClosingDebit || flags.ClosingCredit):
+		return newPreflightFailure(types.TransferFlagsAreMutuallyExclusive, nil)
+	case (flags.ClosingDebit || flags.ClosingCredit) && !flags.Pending:
+		return newPreflightFailure(types.TransferClosingTransferMustBePending, nil)
+	case t.timeout != 0 && !flags.Pending:
+		return newPreflightFailure(types.TransferTimeoutReservedForPendingTransfer, nil)
+	}
+
+	// Post & void inherit accounts, ledger and code from the pending transfer.
+	if postOrVoid {
+		switch {
+		case t.pendingID.IsZero():
+			return newPreflightFailure(types.TransferPendingIDMustNotBeZero, nil)
+		case t.pendingID == maxUint128:
+			return newPreflightFailure(types.TransferPendingIDMustNotBeIntMax, nil)
+		case t.pendingID == id:
+			return newPreflightFailure(types.TransferPendingIDMustBeDifferent, nil)
+		}
+		return preflightFailure{}
+	}
+
+	var ledger LedgerCode
+	if t.Ledger != nil {
+		ledger = t.Ledger.EncodeLedger()
+	}
+	switch {
+	case !t.pendingID.IsZero():
+		return newPreflightFailure(types.TransferPendingIDMustBeZero, nil)
+	case t.DebitAccountID.IsZero():
+		return newPreflightFailure(types.TransferDebitAccountIDMustNotBeZero, nil)
+	case t.DebitAccountID == maxUint128:
+		return newPreflightFailure(types.TransferDebitAccountIDMustNotBeIntMax, nil)
+	case t.CreditAccountID.IsZero():
+		return newPreflightFailure(types.TransferCreditAccountIDMustNotBeZero, nil)
+	case t.CreditAccountID == maxUint128:
+		return newPreflightFailure(types.TransferCreditAccountIDMustNotBeIntMax, nil)
+	case t.DebitAccountID == t.CreditAccountID:
+		return newPreflightFailure(types.TransferAccountsMustBeDifferent, nil)
+	case ledger == 0:
+		return newPreflightFailure(types.TransferLedgerMustNotBeZero, nil)
+	case t.code == 0:
+		return newPreflightFailure(types.TransferCodeMustNotBeZero, nil)
+	}
+	if err := checkAmountLedger(t.Amount, t.Ledger); err != nil {
+		return newPreflightFailure(types.TransferTransferMustHaveTheSameLedgerAsAccounts, err)
+	}
+	if amount.IsZero() && !flags.Pending && !flags.BalancingDebit && !flags.BalancingCredit {
+		return newPreflightFailure(TransferPreflightZeroAmount, nil)
+	}
+	return preflightFailure{}
+}
+
+// checkAmountLedger returns ErrCurrencyAmountMismatch if the amount currency is not the ledger currency.
+// Amounts & ledgers of unknown currency, e.g. custom implementations, are not checked.
+func checkAmountLedger(amount Amount, ledger Ledger) error {
+	a, ok := amount.(*amountCurrency)
+	if !ok || a == nil {
+		return nil
+	}
+	var cur *Currency
+	switch l := ledger.(type) {
+	case *Currency:
+		cur = l
+	case TenantLedger:
+		cur = l.Currency
+	}
+	if cur == nil || sameCurrency(a.curr, cur) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s amount on %s ledger", ErrCurrencyAmountMismatch, a.curr.code, cur.code)
+}
+
+// compareTransfer returns the result of creating b with the ID of the already created a,
+// TransferExists if they are identical.
+func compareTransfer(a, b TransferData) CreateTransferResult {
+	var amountA, amountB Uint128
+	if a.Amount != nil {
+		amountA = a.Amount.ToUint128FromValue()
+	}
+	if b.Amount != nil {
+		amountB = b.Amount.ToUint128FromValue()
+	}
+	var ledgerA, ledgerB LedgerCode
+	if a.Ledger != nil {
+		ledgerA = a.Ledger.EncodeLedger()
+	}
+	if b.Ledger != nil {
+		ledgerB = b.Ledger.EncodeLedger()
+	}
+
+	switch {
+	case a.flags != b.flags:
+		return types.TransferExistsWithDifferentFlags
+	case a.pendingID != b.pendingID:
+		return types.TransferExistsWithDifferentPendingID
+	case a.timeout != b.timeout:
+		return types.TransferExistsWithDifferentTimeout
+	case a.DebitAccountID != b.DebitAccountID:
+		return types.TransferExistsWithDifferentDebitAccountID
+	case a.CreditAccountID != b.CreditAccountID:
+		return types.TransferExistsWithDifferentCreditAccountID
+	case amountA != amountB:
+		return types.TransferExistsWithDifferentAmount
+	case a.UserData128 != b.UserData128:
+		return types.TransferExistsWithDifferentUserData128
+	case a.UserData64 != b.UserData64:
+		return types.TransferExistsWithDifferentUserData64
+	case a.UserData32 != b.UserData32:
+		return types.TransferExistsWithDifferentUserData32
+	case ledgerA != ledgerB:
+		return types.TransferExistsWithDifferentLedger
+	case a.code != b.code:
+		return types.TransferExistsWithDifferentCode
+	}
+	return types.TransferExists
+}
@@ -0,0 +1,186 @@
+package tbdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+// preflightTransferData returns a valid transfer.
+func preflightTransferData(id uint64) TransferData {
+	return TransferData{
+		ID:              Uint128FromUint64(id),
+		DebitAccountID:  Uint128FromUint64(100),
+		CreditAccountID: Uint128FromUint64(200),
+		Amount:          IDR.NewAmountFromMinorUnits(Uint128FromUint64(1000)),
+		Ledger:          IDR,
+		code:            1,
+	}
+}
+
+func TestPreflightTransfer(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(t *TransferData)
+		want   CreateTransferResult
+	}{
+		{"valid", nil, types.TransferOK},
+		{"zero id", func(t *TransferData) { t.ID = Uint128{} }, types.TransferOK},
+		{"id int max", func(t *TransferData) { t.ID = maxUint128 }, types.TransferIDMustNotBeIntMax},
+		{"timestamp", func(t *TransferData) { t.timestamp = 1 }, types.TransferTimestampMustBeZero},
+		{"pending & post", func(t *TransferData) {
+			t.flags = TransferFlags{Pending: true, PostPendingTransfer: true}
+		}, types.TransferFlagsAreMutuallyExclusive},
+		{"void & balancing", func(t *TransferData) {
+			t.flags = TransferFlags{VoidPendingTransfer: true, BalancingDebit: true}
+		}, types.TransferFlagsAreMutuallyExclusive},
+		{"closing not pending", func(t *TransferData) {
+			t.flags = TransferFlags{ClosingDebit: true}
+		}, types.TransferClosingTransferMustBePending},
+		{"timeout not pending", func(t *TransferData) { t.timeout = 60 }, types.TransferTimeoutReservedForPendingTransfer},
+		{"post without pending id", func(t *TransferData) {
+			t.flags = TransferFlags{PostPendingTransfer: true}
+		}, types.TransferPendingIDMustNotBeZero},
+		{"post same pending id", func(t *TransferData) {
+			t.flags, t.pendingID = TransferFlags{PostPendingTransfer: true}, t.ID
+		}, types.TransferPendingIDMustBeDifferent},
+		{"void inherits ledger", func(t *TransferData) {
+			t.flags, t.pendingID, t.Ledger, t.Amount, t.code = TransferFlags{VoidPendingTransfer: true}, Uint128FromUint64(9), nil, nil, 0
+		}, types.TransferOK},
+		{"pending id not resolving", func(t *TransferData) { t.pendingID = Uint128FromUint64(9) }, types.TransferPendingIDMustBeZero},
+		{"zero debit account", func(t *TransferData) { t.DebitAccountID = Uint128{} }, types.TransferDebitAccountIDMustNotBeZero},
+		{"credit account int max", func(t *TransferData) { t.CreditAccountID = maxUint128 }, types.TransferCreditAccountIDMustNotBeIntMax},
+		{"same accounts", func(t *TransferData) { t.CreditAccountID = t.DebitAccountID }, types.TransferAccountsMustBeDifferent},
+		{"nil ledger", func(t *TransferData) { t.Ledger = nil }, types.TransferLedgerMustNotBeZero},
+		{"zero code", func(t *TransferData) { t.code = 0 }, types.TransferCodeMustNotBeZero},
+		{"currency mismatch", func(t *TransferData) { t.Ledger = BTC }, types.TransferTransferMustHaveTheSameLedgerAsAccounts},
+		{"tenant currency mismatch", func(t *TransferData) {
+			t.Ledger = TenantLedger{Tenant: 1, Currency: USD}
+		}, types.TransferTransferMustHaveTheSameLedgerAsAccounts},
+		{"zero amount", func(t *TransferData) { t.Amount = IDR.NewMonetary() }, TransferPreflightZeroAmount},
+		{"nil amount", func(t *TransferData) { t.Amount = nil }, TransferPreflightZeroAmount},
+		{"zero amount pending", func(t *TransferData) {
+			t.Amount, t.flags = IDR.NewMonetary(), TransferFlags{Pending: true}
+		}, types.TransferOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := PreflightTransfers([]TransferData{modified(preflightTransferData(1), tt.modify)})
+			got := result.Results[0]
+			assert.Equal(t, tt.want, got.Result, "PreflightTransfers returned unexpected result %s", got.Result)
+			if tt.want == types.TransferOK {
+				assert.NoError(t, got.Err)
+				assert.Equal(t, 1, result.SuccessCount)
+				return
+			}
+			assert.ErrorIs(t, got.Err, ErrTransferPreflight)
+			assert.Equal(t, 1, result.FailedCount)
+		})
+	}
+
+	result := PreflightTransfers([]TransferData{modified(preflightTransferData(1), func(t *TransferData) { t.Ledger = BTC })})
+	assert.ErrorIs(t, result.Results[0].Err, ErrCurrencyAmountMismatch)
+
+	result = PreflightTransfers([]TransferData{modified(preflightTransferData(1), func(t *TransferData) { t.Amount = nil })})
+	assert.ErrorIs(t, result.Results[0].Err, ErrZeroAmount)
+	assert.Contains(t, result.Results[0].Err.Error(), "TransferPreflightZeroAmount")
+}
+
+func TestPreflightTransfersBatch(t *testing.T) {
+	linked := func(t *TransferData) { t.flags.Linked = true }
+	invalid := func(t *TransferData) { t.code = 0 }
+
+	tests := []struct {
+		name      string
+		transfers []TransferData
+		want      []CreateTransferResult
+	}{
+		{
+			"duplicate identical",
+			[]TransferData{preflightTransferData(1), preflightTransferData(1)},
+			[]CreateTransferResult{types.TransferOK, types.TransferExists},
+		},
+		{
+			"duplicate different amount",
+			[]TransferData{preflightTransferData(1), modified(preflightTransferData(1), func(t *TransferData) {
+				t.Amount = IDR.NewAmountFromMinorUnits(Uint128FromUint64(1))
+			})},
+			[]CreateTransferResult{types.TransferOK, types.TransferExistsWithDifferentAmount},
+		},
+		{
+			"duplicate of failed",
+			[]TransferData{modified(preflightTransferData(1), invalid), preflightTransferData(1)},
+			[]CreateTransferResult{types.TransferCodeMustNotBeZero, types.TransferOK},
+		},
+		{
+			"failed chain",
+			[]TransferData{
+				modified(preflightTransferData(1), linked),
+				modified(preflightTransferData(2), func(t *TransferData) { linked(t); invalid(t) }),
+				preflightTransferData(3),
+				preflightTransferData(4),
+			},
+			[]CreateTransferResult{
+				types.TransferLinkedEventFailed, types.TransferCodeMustNotBeZero, types.TransferLinkedEventFailed, types.TransferOK,
+			},
+		},
+		{
+			"rolled back chain frees id",
+			[]TransferData{
+				modified(preflightTransferData(1), linked),
+				modified(preflightTransferData(2), invalid),
+				preflightTransferData(1),
+			},
+			[]CreateTransferResult{types.TransferLinkedEventFailed, types.TransferCodeMustNotBeZero, types.TransferOK},
+		},
+		{
+			"chain open",
+			[]TransferData{preflightTransferData(1), modified(preflightTransferData(2), linked)},
+			[]CreateTransferResult{types.TransferOK, types.TransferLinkedEventChainOpen},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := PreflightTransfers(tt.transfers)
+			got := make([]CreateTransferResult, 0, len(result.Results))
+			for _, r := range result.Results {
+				got = append(got, r.Result)
+			}
+			assert.Equal(t, tt.want, got, "PreflightTransfers returned unexpected result")
+		})
+	}
+}
+
+func TestDoTransfersPreflight(t *testing.T) {
+	client := &fakeClient{}
+	instance := &Instance{client: client}
+	instance.SetTransferPreflight(true)
+	_, err := instance.CreateTransfers([]TransferData{preflightTransferData(2)})
+	assert.NoError(t, err)
+
+	result, err := instance.CreateTransfers([]TransferData{
+		modified(preflightTransferData(1), func(t *TransferData) { t.Ledger = nil }),
+		modified(preflightTransferData(2), func(t *TransferData) { t.Amount = IDR.NewAmountFromMinorUnits(Uint128FromUint64(5)) }),
+		preflightTransferData(3),
+	})
+	assert.NoError(t, err)
+	_, sent := client.transfer(toBinding(Uint128FromUint64(1)))
+	assert.False(t, sent, "rejected transfers must not be sent")
+	assert.Len(t, client.transfers, 2)
+	assert.Equal(t, 1, result.SuccessCount)
+	assert.Equal(t, 2, result.FailedCount)
+	assert.Equal(t, types.TransferLedgerMustNotBeZero, result.Results[0].Result)
+	assert.Equal(t, types.TransferExistsWithDifferentAmount, result.Results[1].Result, "cluster results must map to the batch index")
+	assert.Equal(t, uint32(1), result.Results[1].Index)
+	assert.Equal(t, types.TransferOK, result.Results[2].Result)
+
+	// Nothing left to send.
+	calls := client.calls
+	result, err = instance.CreateTransfers([]TransferData{modified(preflightTransferData(1), func(t *TransferData) { t.Ledger = nil })})
+	assert.NoError(t, err)
+	assert.Equal(t, calls, client.calls)
+	assert.Equal(t, 1, result.FailedCount)
+}
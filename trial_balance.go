@@ -0,0 +1,283 @@
+package tbdb
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	tb "github.com/tigerbeetle/tigerbeetle-go"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+// TrialBalanceFilter selects the accounts of a trial balance, by ids or by query.
+type TrialBalanceFilter struct {
+	// AccountIDs are the accounts of the trial balance, duplicates are counted once.
+	// Optional; if empty, the accounts are queried with the fields below, which narrow the ids otherwise.
+	AccountIDs []Uint128
+	// Filter the accounts by 128-bit user-defined data.
+	// Optional; set to zero to disable the filter
+	UserData128 Uint128
+	// Filter the accounts by 64-bit user-defined data.
+	// Optional; set to zero to disable the filter
+	UserData64 uint64
+	// Filter the accounts by 32-bit user-defined data.
+	// Optional; set to zero to disable the filter
+	UserData32 uint32
+	// Filter the accounts by ledger.
+	// Optional; set to nil to disable the filter
+	Ledger Ledger
+	// Filter the accounts by category, i.e. account code.
+	// Optional; set to zero to disable the filter
+	Category AccountCategory
+}
+
+// SetUserData sets the user-defined data filters from the tagged struct, see EncodeUserData.
+// Filters match whole fields and zero fields are disabled, so set every member packed into a filtered field.
+func (f *TrialBalanceFilter) SetUserData(v any) error {
+	data, err := EncodeUserData(v)
+	if err != nil {
+		return err
+	}
+	f.UserData128, f.UserData64, f.UserData32 = data.UserData128, data.UserData64, data.UserData32
+	return nil
+}
+
+// matches reports whether the account passes the filter fields.
+func (f TrialBalanceFilter) matches(account types.Account) bool {
+	switch {
+	case !f.UserData128.IsZero() && f.UserData128 != fromBinding(account.UserData128):
+		return false
+	case f.UserData64 != 0 && f.UserData64 != account.UserData64:
+		return false
+	case f.UserData32 != 0 && f.UserData32 != account.UserData32:
+		return false
+	case f.Ledger != nil && uint32(f.Ledger.EncodeLedger()) != account.Ledger:
+		return false
+	case f.Category != 0 && uint16(f.Category) != account.Code:
+		return false
+	}
+	return true
+}
+
+// TrialBalanceTotals defines the debits & credits totals of a group of accounts, in minor units.
+type TrialBalanceTotals struct {
+	// Accounts is the number of accounts of the group.
+	Accounts int `json:"accounts"`
+	// DebitsPending is the sum of the pending debits.
+	DebitsPending Uint128Decimal `json:"debits_pending"`
+	// DebitsPosted is the sum of the posted debits.
+	DebitsPosted Uint128Decimal `json:"debits_posted"`
+	// CreditsPending is the sum of the pending credits.
+	CreditsPending Uint128Decimal `json:"credits_pending"`
+	// CreditsPosted is the sum of the posted credits.
+	CreditsPosted Uint128Decimal `json:"credits_posted"`
+}
+
+// add adds the account debits & credits to the totals, returns ErrUint128Overflow if any sum overflows.
+func (t *TrialBalanceTotals) add(account types.Account) error {
+	sums := []struct {
+		total *Uint128Decimal
+		value types.Uint128
+		name  string
+	}{
+		{&t.DebitsPending, account.DebitsPending, "debits pending"},
+		{&t.DebitsPosted, account.DebitsPosted, "debits posted"},
+		{&t.CreditsPending, account.CreditsPending, "credits pending"},
+		{&t.CreditsPosted, account.CreditsPosted, "credits posted"},
+	}
+	for _, sum := range sums {
+		total, overflow := Uint128(*sum.total).Add(fromBinding(sum.value))
+		if overflow {
+			return fmt.Errorf("%w: %s", ErrUint128Overflow, sum.name)
+		}
+		*sum.total = Uint128Decimal(total)
+	}
+	t.Accounts++
+	return nil
+}
+
+// TrialBalanceCategory defines the totals of the accounts of a category in a ledger.
+type TrialBalanceCategory struct {
+	// Category is the account category, i.e. account code, which may be outside the built-in categories.
+	Category AccountCategory `json:"category"`
+	TrialBalanceTotals
+}
+
+// TrialBalanceLedger defines the totals of the accounts of a ledger.
+type TrialBalanceLedger struct {
+	// Ledger is the raw TigerBeetle ledger.
+	Ledger uint32 `json:"ledger"`
+	// Currency is the currency code of the ledger, empty if the ledger can not be parsed, see ParseLedgerCode.
+	Currency string `json:"currency,omitempty"`
+	// Categories are the totals by category, in category order.
+	Categories []TrialBalanceCategory `json:"categories"`
+	// Totals are the totals of the ledger.
+	Totals TrialBalanceTotals `json:"totals"`
+	// Balanced reports whether the ledger nets to zero: posted and pending debits equal the credits.
+	Balanced bool `json:"balanced"`
+}
+
+// TrialBalance defines the totals of a set of accounts by ledger & category.
+type TrialBalance struct {
+	// Ledgers are the totals by ledger, in ledger order.
+	Ledgers []TrialBalanceLedger `json:"ledgers"`
+	// Balanced reports whether every ledger is balanced.
+	Balanced bool `json:"balanced"`
+}
+
+// GetTrialBalance returns the debits & credits posted and pending of the filter accounts, totalled by ledger &
+// category with exact Uint128 arithmetic, and whether each ledger nets to zero.
+//
+// Every transfer debits & credits accounts of the same ledger, so a ledger is balanced when the trial balance covers
+// all of its accounts; a subset of accounts, e.g. by user data, is usually not.
+// Returns ErrAccountNotFound if an account id does not exist and ErrUint128Overflow if a total overflows.
+func (i *Instance) GetTrialBalance(filter TrialBalanceFilter) (*TrialBalance, error) {
+	return i.trialBalance(filter, nil)
+}
+
+// trialBalance returns the trial balance of the filter accounts in the scope of the ledger check:
+// ids out of scope are errors, queried accounts out of scope are skipped.
+func (i *Instance) trialBalance(filter TrialBalanceFilter, scope func(ledger uint32) error) (*TrialBalance, error) {
+	cln, err := i.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	// Get accounts.
+	var accounts []types.Account
+	if len(filter.AccountIDs) > 0 {
+		found, err := trialBalanceLookup(cln, filter.AccountIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, account := range found {
+			if scope != nil {
+				if err := scope(account.Ledger); err != nil {
+					return nil, fmt.Errorf("account %s: %w", fromBinding(account.ID).String(), err)
+				}
+			}
+			if filter.matches(account) {
+				accounts = append(accounts, account)
+			}
+		}
+	} else {
+		found, err := trialBalanceQuery(cln, filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, account := range found {
+			if scope == nil || scope(account.Ledger) == nil {
+				accounts = append(accounts, account)
+			}
+		}
+	}
+	return newTrialBalance(accounts)
+}
+
+// trialBalanceLookup looks up the accounts by ids in batches, returns ErrAccountNotFound for missing ids.
+func trialBalanceLookup(cln tb.Client, ids []Uint128) ([]types.Account, error) {
+	seen := make(map[Uint128]struct{}, len(ids))
+	tbIds := make([]types.Uint128, 0, len(ids))
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		tbIds = append(tbIds, toBinding(id))
+	}
+
+	accounts := make([]types.Account, 0, len(tbIds))
+	for batch := range slices.Chunk(tbIds, int(TigerBeetleMaxBatch)) {
+		found, err := cln.LookupAccounts(batch)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, found...)
+	}
+	if len(accounts) < len(tbIds) {
+		for _, account := range accounts {
+			delete(seen, fromBinding(account.ID))
+		}
+		for _, id := range ids {
+			if _, missing := seen[id]; missing {
+				return nil, fmt.Errorf("%w: %s", ErrAccountNotFound, id.String())
+			}
+		}
+	}
+	return accounts, nil
+}
+
+// trialBalanceQuery queries the accounts of the filter fields page by page.
+func trialBalanceQuery(cln tb.Client, filter TrialBalanceFilter) ([]types.Account, error) {
+	query := types.QueryFilter{
+		UserData128: toBinding(filter.UserData128),
+		UserData64:  filter.UserData64,
+		UserData32:  filter.UserData32,
+		Code:        uint16(filter.Category),
+		Limit:       uint32(TigerBeetleMaxBatch),
+	}
+	if filter.Ledger != nil {
+		query.Ledger = uint32(filter.Ledger.EncodeLedger())
+	}
+
+	var accounts []types.Account
+	for {
+		found, err := cln.QueryAccounts(query)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, found...)
+		if len(found) < int(query.Limit) {
+			return accounts, nil
+		}
+		query.TimestampMin = found[len(found)-1].Timestamp + 1
+	}
+}
+
+// newTrialBalance totals the accounts by ledger & category.
+func newTrialBalance(accounts []types.Account) (*TrialBalance, error) {
+	type ledgerTotals struct {
+		ledger     TrialBalanceLedger
+		categories map[AccountCategory]*TrialBalanceCategory
+	}
+	ledgers := make(map[uint32]*ledgerTotals)
+	for _, account := range accounts {
+		totals, ok := ledgers[account.Ledger]
+		if !ok {
+			totals = &ledgerTotals{
+				ledger:     TrialBalanceLedger{Ledger: account.Ledger},
+				categories: make(map[AccountCategory]*TrialBalanceCategory),
+			}
+			if cur, err := ParseLedgerCode(account.Ledger); err == nil {
+				totals.ledger.Currency = cur.Code()
+			}
+			ledgers[account.Ledger] = totals
+		}
+		category := AccountCategory(account.Code)
+		categoryTotals, ok := totals.categories[category]
+		if !ok {
+			categoryTotals = &TrialBalanceCategory{Category: category}
+			totals.categories[category] = categoryTotals
+		}
+		if err := categoryTotals.add(account); err != nil {
+			return nil, fmt.Errorf("ledger %d category %d: %w", account.Ledger, category, err)
+		}
+		if err := totals.ledger.Totals.add(account); err != nil {
+			return nil, fmt.Errorf("ledger %d: %w", account.Ledger, err)
+		}
+	}
+
+	// In ledger & category order.
+	trial := &TrialBalance{Ledgers: make([]TrialBalanceLedger, 0, len(ledgers)), Balanced: true}
+	for _, ledger := range slices.Sorted(maps.Keys(ledgers)) {
+		totals := ledgers[ledger]
+		for _, category := range slices.Sorted(maps.Keys(totals.categories)) {
+			totals.ledger.Categories = append(totals.ledger.Categories, *totals.categories[category])
+		}
+		sums := totals.ledger.Totals
+		totals.ledger.Balanced = sums.DebitsPosted == sums.CreditsPosted && sums.DebitsPending == sums.CreditsPending
+		trial.Balanced = trial.Balanced && totals.ledger.Balanced
+		trial.Ledgers = append(trial.Ledgers, totals.ledger)
+	}
+	return trial, nil
+}
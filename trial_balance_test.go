@@ -0,0 +1,203 @@
+package tbdb
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+// testTrialBalanceFixture is a balanced IDR ledger: control 1 funded wallets 2 & 3 with 10.00,
+// wallet 2 holds 0.50 for income 4; and an unbalanced USD ledger of wallet 5 & control 6.
+func testTrialBalanceFixture() fakeClient {
+	idr, usd := uint32(IDR.EncodeLedger()), uint32(USD.EncodeLedger())
+	account := func(id uint64, ledger uint32, category AccountCategory, userData64 uint64, sides ...uint64) types.Account {
+		return types.Account{
+			ID:             types.ToUint128(id),
+			DebitsPending:  types.ToUint128(sides[0]),
+			DebitsPosted:   types.ToUint128(sides[1]),
+			CreditsPending: types.ToUint128(sides[2]),
+			CreditsPosted:  types.ToUint128(sides[3]),
+			UserData64:     userData64,
+			Ledger:         ledger,
+			Code:           uint16(category),
+			Timestamp:      id,
+		}
+	}
+	return fakeClient{accounts: []types.Account{
+		account(1, idr, AccountCategoryControl, 0, 0, 1000, 0, 0),
+		account(2, idr, AccountCategoryBalance, 77, 50, 0, 0, 700),
+		account(3, idr, AccountCategoryBalance, 77, 0, 0, 0, 300),
+		account(4, idr, AccountCategoryIncome, 0, 0, 0, 50, 0),
+		account(5, usd, AccountCategoryBalance, 0, 0, 0, 0, 100),
+		account(6, usd, AccountCategoryControl, 0, 0, 90, 0, 0),
+	}}
+}
+
+func TestGetTrialBalance(t *testing.T) {
+	fake := testTrialBalanceFixture()
+	i := &Instance{client: &fake}
+
+	trial, err := i.GetTrialBalance(TrialBalanceFilter{})
+	require.NoError(t, err)
+	require.Len(t, trial.Ledgers, 2)
+	assert.False(t, trial.Balanced)
+	assert.Less(t, trial.Ledgers[0].Ledger, trial.Ledgers[1].Ledger, "ledger order")
+
+	ledgers := make(map[string]TrialBalanceLedger)
+	for _, ledger := range trial.Ledgers {
+		ledgers[ledger.Currency] = ledger
+	}
+	idr := ledgers["IDR"]
+	assert.True(t, idr.Balanced)
+	assert.Equal(t, TrialBalanceTotals{
+		Accounts:       4,
+		DebitsPending:  Uint128Decimal(Uint128FromUint64(50)),
+		DebitsPosted:   Uint128Decimal(Uint128FromUint64(1000)),
+		CreditsPending: Uint128Decimal(Uint128FromUint64(50)),
+		CreditsPosted:  Uint128Decimal(Uint128FromUint64(1000)),
+	}, idr.Totals)
+	require.Len(t, idr.Categories, 3)
+	assert.Equal(t, AccountCategoryControl, idr.Categories[0].Category)
+	assert.Equal(t, AccountCategoryBalance, idr.Categories[1].Category)
+	assert.Equal(t, 2, idr.Categories[1].Accounts)
+	assert.Equal(t, Uint128Decimal(Uint128FromUint64(1000)), idr.Categories[1].CreditsPosted)
+	assert.Equal(t, AccountCategoryIncome, idr.Categories[2].Category)
+
+	usd := ledgers["USD"]
+	assert.False(t, usd.Balanced)
+	assert.Equal(t, Uint128Decimal(Uint128FromUint64(90)), usd.Totals.DebitsPosted)
+	assert.Equal(t, Uint128Decimal(Uint128FromUint64(100)), usd.Totals.CreditsPosted)
+}
+
+func TestGetTrialBalanceFilter(t *testing.T) {
+	ids := func(ids ...uint64) []Uint128 {
+		var result []Uint128
+		for _, id := range ids {
+			result = append(result, Uint128FromUint64(id))
+		}
+		return result
+	}
+	tests := []struct {
+		name     string
+		filter   TrialBalanceFilter
+		accounts int
+		ledgers  int
+		balanced bool
+	}{
+		{"ids", TrialBalanceFilter{AccountIDs: ids(1, 2, 3, 4)}, 4, 1, true},
+		{"duplicate ids", TrialBalanceFilter{AccountIDs: ids(2, 3, 2)}, 2, 1, false},
+		{"ids & category", TrialBalanceFilter{AccountIDs: ids(1, 2, 3, 5), Category: AccountCategoryBalance}, 3, 2, false},
+		{"ids & ledger", TrialBalanceFilter{AccountIDs: ids(1, 2, 5, 6), Ledger: USD}, 2, 1, false},
+		{"user data", TrialBalanceFilter{UserData64: 77}, 2, 1, false},
+		{"category", TrialBalanceFilter{Category: AccountCategoryControl}, 2, 2, false},
+		{"ledger", TrialBalanceFilter{Ledger: IDR}, 4, 1, true},
+		{"none", TrialBalanceFilter{UserData32: 1}, 0, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := testTrialBalanceFixture()
+			i := &Instance{client: &fake}
+			trial, err := i.GetTrialBalance(tt.filter)
+			require.NoError(t, err)
+			assert.Len(t, trial.Ledgers, tt.ledgers)
+			assert.Equal(t, tt.balanced, trial.Balanced)
+			var accounts int
+			for _, ledger := range trial.Ledgers {
+				accounts += ledger.Totals.Accounts
+			}
+			assert.Equal(t, tt.accounts, accounts)
+		})
+	}
+}
+
+func TestGetTrialBalanceErrors(t *testing.T) {
+	fake := testTrialBalanceFixture()
+	i := &Instance{client: &fake}
+	_, err := i.GetTrialBalance(TrialBalanceFilter{AccountIDs: []Uint128{Uint128FromUint64(1), Uint128FromUint64(9)}})
+	assert.ErrorIs(t, err, ErrAccountNotFound)
+
+	maxValue := types.BytesToUint128([16]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})
+	overflow := &fakeClient{accounts: []types.Account{
+		{ID: types.ToUint128(1), CreditsPosted: maxValue, Ledger: 1, Timestamp: 1},
+		{ID: types.ToUint128(2), CreditsPosted: types.ToUint128(1), Ledger: 1, Timestamp: 2},
+	}}
+	_, err = (&Instance{client: overflow}).GetTrialBalance(TrialBalanceFilter{})
+	assert.ErrorIs(t, err, ErrUint128Overflow)
+}
+
+func TestGetTrialBalancePages(t *testing.T) {
+	client := &fakeClient{}
+	count := int(TigerBeetleMaxBatch) + 10
+	for idx := 1; idx <= count; idx++ {
+		client.accounts = append(client.accounts, types.Account{
+			ID:            types.ToUint128(uint64(idx)),
+			CreditsPosted: types.ToUint128(1),
+			Ledger:        1,
+			Timestamp:     uint64(idx),
+		})
+	}
+	trial, err := (&Instance{client: client}).GetTrialBalance(TrialBalanceFilter{})
+	require.NoError(t, err)
+	require.Len(t, trial.Ledgers, 1)
+	assert.Equal(t, count, trial.Ledgers[0].Totals.Accounts)
+	assert.Empty(t, trial.Ledgers[0].Currency)
+}
+
+func TestTrialBalanceJSON(t *testing.T) {
+	fake := testTrialBalanceFixture()
+	trial, err := (&Instance{client: &fake}).GetTrialBalance(TrialBalanceFilter{Ledger: USD})
+	require.NoError(t, err)
+	data, err := json.Marshal(trial)
+	require.NoError(t, err)
+
+	var decoded struct {
+		Ledgers []struct {
+			Currency   string `json:"currency"`
+			Categories []struct {
+				Category      uint16 `json:"category"`
+				Accounts      int    `json:"accounts"`
+				CreditsPosted string `json:"credits_posted"`
+			} `json:"categories"`
+			Totals struct {
+				DebitsPosted string `json:"debits_posted"`
+			} `json:"totals"`
+			Balanced bool `json:"balanced"`
+		} `json:"ledgers"`
+		Balanced bool `json:"balanced"`
+	}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Len(t, decoded.Ledgers, 1)
+	assert.Equal(t, "USD", decoded.Ledgers[0].Currency)
+	assert.Equal(t, "90", decoded.Ledgers[0].Totals.DebitsPosted)
+	assert.Equal(t, uint16(AccountCategoryBalance), decoded.Ledgers[0].Categories[1].Category)
+	assert.Equal(t, "100", decoded.Ledgers[0].Categories[1].CreditsPosted)
+	assert.False(t, decoded.Balanced)
+}
+
+func TestTenantTrialBalance(t *testing.T) {
+	own := TenantLedger{Tenant: 42, Currency: IDR}
+	other := TenantLedger{Tenant: 7, Currency: IDR}
+	client := &fakeClient{accounts: []types.Account{
+		{ID: types.ToUint128(1), DebitsPosted: types.ToUint128(5), Ledger: uint32(own.EncodeLedger()), Timestamp: 1},
+		{ID: types.ToUint128(2), CreditsPosted: types.ToUint128(5), Ledger: uint32(own.EncodeLedger()), Timestamp: 2},
+		{ID: types.ToUint128(3), CreditsPosted: types.ToUint128(9), Ledger: uint32(other.EncodeLedger()), Timestamp: 3},
+	}}
+	tenant, err := (&Instance{client: client}).Tenant(42)
+	require.NoError(t, err)
+
+	trial, err := tenant.GetTrialBalance(TrialBalanceFilter{})
+	require.NoError(t, err)
+	require.Len(t, trial.Ledgers, 1)
+	assert.Equal(t, 2, trial.Ledgers[0].Totals.Accounts, "accounts of other tenant are skipped")
+	assert.True(t, trial.Balanced)
+
+	trial, err = tenant.GetTrialBalance(TrialBalanceFilter{Ledger: IDR})
+	require.NoError(t, err)
+	assert.Equal(t, uint32(own.EncodeLedger()), trial.Ledgers[0].Ledger, "ledger stamped")
+
+	_, err = tenant.GetTrialBalance(TrialBalanceFilter{AccountIDs: []Uint128{Uint128FromUint64(1), Uint128FromUint64(3)}})
+	assert.ErrorIs(t, err, ErrTenantMismatch)
+}
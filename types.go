@@ -42,6 +42,12 @@ type Amount interface {
 	// thousand separators, decimal precision, etc.).
 	Uint128ToString() string
 
+	// Format formats the Uint128 amount with the given options, exact for every representable amount.
+	Format(opts FormatOptions) string
+
+	// FormatWith formats the Uint128 amount with the given locale-aware formatter.
+	FormatWith(f Formatter, opts FormatOptions) string
+
 	// Add safely adds two Uint128 amounts.
 	// Returns error if the operation would cause overflow or violates
 	// implementation-specific constraints.
@@ -67,6 +73,20 @@ type Amount interface {
 	// are handled for the specific monetary unit.
 	Percentage(percent float64) (Uint128, error)
 
+	// MulDiv multiplies an amount by num/den exactly and rounds with the given mode.
+	// The result carries the remainder, so the calculation can be audited.
+	MulDiv(num, den Uint128, mode RoundingMode) (RoundResult, error)
+
+	// MulRatio multiplies an amount by an exact rational factor and rounds with the given mode.
+	MulRatio(r Ratio, mode RoundingMode) (Uint128, error)
+
+	// DivRatio divides an amount by an exact rational divisor and rounds with the given mode.
+	// Returns ErrDivisionByZero if the divisor is zero.
+	DivRatio(r Ratio, mode RoundingMode) (Uint128, error)
+
+	// PercentageRatio calculates an exact rational percentage of an amount and rounds with the given mode.
+	PercentageRatio(percent Ratio, mode RoundingMode) (Uint128, error)
+
 	// Compare compares two Uint128 amounts according to implementation rules.
 	// Returns:
 	//	- -1 if a < b
@@ -0,0 +1,308 @@
+package tbdb
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// UserData defines the user-defined data fields of accounts & transfers.
+type UserData struct {
+	// UserData128 is 128-bit user-defined data.
+	UserData128 Uint128
+	// UserData64 is 64-bit user-defined data.
+	UserData64 uint64
+	// UserData32 is 32-bit user-defined data.
+	UserData32 uint32
+}
+
+// userDataField defines the user-defined data field of a struct member, by its bit size.
+type userDataField uint8
+
+// Enum of user-defined data field.
+const (
+	userDataField128 userDataField = iota
+	userDataField64
+	userDataField32
+)
+
+// size returns the field bit size.
+func (f userDataField) size() uint {
+	switch f {
+	case userDataField64:
+		return 64
+	case userDataField32:
+		return 32
+	}
+	return 128
+}
+
+// userDataMember defines a struct member packed into a user-defined data field.
+type userDataMember struct {
+	name  string
+	index []int
+	field userDataField
+	lo    uint
+	width uint
+}
+
+// userDataLayouts caches the layout of struct types, *userDataLayout by reflect.Type.
+var userDataLayouts sync.Map
+
+// userDataLayout defines the members of a struct type.
+type userDataLayout struct {
+	members []userDataMember
+	err     error
+}
+
+// EncodeUserData encodes the tagged members of a struct, or a pointer to struct, into the user-defined data fields.
+// Members are tagged with the field and its inclusive bit range, the range is the whole field if omitted:
+//
+//	type PaymentMeta struct {
+//		MerchantID uint64 `tbdb:"ud128,bits=0-63"`
+//		Reference  uint64 `tbdb:"ud128,bits=64-127"`
+//		Channel    uint8  `tbdb:"ud64,bits=0-7"`
+//		Region     uint16 `tbdb:"ud64,bits=8-23"`
+//		Refund     bool   `tbdb:"ud32,bits=31"`
+//	}
+//
+// Supported member types are unsigned integers, bool and Uint128.
+// Returns ErrUserDataOverflow if a value does not fit its bits and ErrInvalidUserDataTag on invalid layout.
+func EncodeUserData(v any) (UserData, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return UserData{}, fmt.Errorf("%w: %T is not a struct", ErrInvalidUserDataTag, v)
+	}
+	layout := userDataLayoutOf(rv.Type())
+	if layout.err != nil {
+		return UserData{}, layout.err
+	}
+
+	var fields [3]Uint128
+	for _, member := range layout.members {
+		value := userDataValue(rv.FieldByIndex(member.index))
+		if value.BitLen() > int(member.width) {
+			return UserData{}, fmt.Errorf("%w: %s needs %d bits, has %d", ErrUserDataOverflow, member.name,
+				value.BitLen(), member.width)
+		}
+		shifted := value.Lsh(member.lo)
+		fields[member.field] = Uint128{Hi: fields[member.field].Hi | shifted.Hi, Lo: fields[member.field].Lo | shifted.Lo}
+	}
+	return UserData{
+		UserData128: fields[userDataField128],
+		UserData64:  fields[userDataField64].Lo,
+		UserData32:  uint32(fields[userDataField32].Lo),
+	}, nil
+}
+
+// DecodeUserData decodes the user-defined data fields into the tagged members of a struct pointer,
+// see EncodeUserData. Untagged members are left untouched.
+func DecodeUserData(data UserData, v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() || rv.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("%w: %T is not a struct pointer", ErrInvalidUserDataTag, v)
+	}
+	rv = rv.Elem()
+	layout := userDataLayoutOf(rv.Type())
+	if layout.err != nil {
+		return layout.err
+	}
+
+	fields := [3]Uint128{data.UserData128, Uint128FromUint64(data.UserData64), Uint128FromUint64(uint64(data.UserData32))}
+	for _, member := range layout.members {
+		value := fields[member.field].Rsh(member.lo)
+		mask := userDataMask(member.width)
+		setUserDataValue(rv.FieldByIndex(member.index), Uint128{Hi: value.Hi & mask.Hi, Lo: value.Lo & mask.Lo})
+	}
+	return nil
+}
+
+// userDataLayoutOf returns the cached layout of the struct type.
+func userDataLayoutOf(t reflect.Type) *userDataLayout {
+	if layout, ok := userDataLayouts.Load(t); ok {
+		return layout.(*userDataLayout)
+	}
+	members, err := parseUserDataLayout(t)
+	layout, _ := userDataLayouts.LoadOrStore(t, &userDataLayout{members: members, err: err})
+	return layout.(*userDataLayout)
+}
+
+// parseUserDataLayout parses the tagged members of the struct type, rejecting overlapping bits.
+func parseUserDataLayout(t reflect.Type) ([]userDataMember, error) {
+	var (
+		members []userDataMember
+		used    [3]Uint128
+	)
+	for idx := range t.NumField() {
+		sf := t.Field(idx)
+		tag, ok := sf.Tag.Lookup("tbdb")
+		if !ok || tag == "-" {
+			continue
+		}
+		typeWidth := userDataTypeWidth(sf.Type)
+		if typeWidth == 0 || !sf.IsExported() {
+			return nil, fmt.Errorf("%w: %s.%s of type %s", ErrInvalidUserDataTag, t.Name(), sf.Name, sf.Type)
+		}
+
+		member := userDataMember{name: t.Name() + "." + sf.Name, index: sf.Index}
+		name, bits, _ := strings.Cut(tag, ",")
+		switch strings.TrimSpace(name) {
+		case "ud128":
+			member.field = userDataField128
+		case "ud64":
+			member.field = userDataField64
+		case "ud32":
+			member.field = userDataField32
+		default:
+			return nil, fmt.Errorf("%w: %s has unknown field %q", ErrInvalidUserDataTag, member.name, name)
+		}
+
+		// Bit range, the whole field if omitted.
+		lo, hi := uint(0), min(typeWidth, member.field.size())-1
+		if bits = strings.TrimSpace(bits); bits != "" {
+			var err error
+			if lo, hi, err = parseUserDataBits(bits); err != nil {
+				return nil, fmt.Errorf("%w: %s: %w", ErrInvalidUserDataTag, member.name, err)
+			}
+		}
+		member.lo, member.width = lo, hi-lo+1
+		switch {
+		case hi >= member.field.size():
+			return nil, fmt.Errorf("%w: %s bits %d-%d exceed %d-bit field", ErrInvalidUserDataTag, member.name,
+				lo, hi, member.field.size())
+		case member.width > typeWidth:
+			return nil, fmt.Errorf("%w: %s bits %d-%d exceed %d-bit type", ErrInvalidUserDataTag, member.name,
+				lo, hi, typeWidth)
+		}
+
+		// Overlapping bits.
+		mask := userDataMask(member.width).Lsh(lo)
+		if used[member.field].Hi&mask.Hi != 0 || used[member.field].Lo&mask.Lo != 0 {
+			return nil, fmt.Errorf("%w: %s bits %d-%d overlap", ErrInvalidUserDataTag, member.name, lo, hi)
+		}
+		used[member.field] = Uint128{Hi: used[member.field].Hi | mask.Hi, Lo: used[member.field].Lo | mask.Lo}
+		members = append(members, member)
+	}
+	return members, nil
+}
+
+// parseUserDataBits parses "bits=lo-hi" or "bits=n".
+func parseUserDataBits(s string) (lo, hi uint, err error) {
+	key, val, ok := strings.Cut(s, "=")
+	if !ok || strings.TrimSpace(key) != "bits" {
+		return 0, 0, fmt.Errorf("invalid option %q", s)
+	}
+	loStr, hiStr, isRange := strings.Cut(strings.TrimSpace(val), "-")
+	if !isRange {
+		hiStr = loStr
+	}
+	loVal, err := strconv.ParseUint(strings.TrimSpace(loStr), 10, 8)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid bits %q", val)
+	}
+	hiVal, err := strconv.ParseUint(strings.TrimSpace(hiStr), 10, 8)
+	if err != nil || hiVal < loVal {
+		return 0, 0, fmt.Errorf("invalid bits %q", val)
+	}
+	return uint(loVal), uint(hiVal), nil
+}
+
+// userDataTypeWidth returns the bit width of supported member type, zero if unsupported.
+func userDataTypeWidth(t reflect.Type) uint {
+	if t == reflect.TypeOf(Uint128{}) {
+		return 128
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return 1
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uint, reflect.Uintptr:
+		return uint(t.Bits())
+	}
+	return 0
+}
+
+// userDataMask returns the mask of the lowest width bits.
+func userDataMask(width uint) Uint128 {
+	if width >= 128 {
+		return Uint128{Hi: ^uint64(0), Lo: ^uint64(0)}
+	}
+	mask, _ := Uint128FromUint64(1).Lsh(width).Sub(Uint128FromUint64(1))
+	return mask
+}
+
+// userDataValue returns the member value as Uint128.
+func userDataValue(v reflect.Value) Uint128 {
+	switch v.Kind() {
+	case reflect.Bool:
+		if v.Bool() {
+			return Uint128FromUint64(1)
+		}
+		return Uint128{}
+	case reflect.Struct:
+		return v.Interface().(Uint128)
+	}
+	return Uint128FromUint64(v.Uint())
+}
+
+// setUserDataValue sets the member value from Uint128.
+func setUserDataValue(v reflect.Value, value Uint128) {
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(!value.IsZero())
+	case reflect.Struct:
+		v.Set(reflect.ValueOf(value))
+	default:
+		v.SetUint(value.Lo)
+	}
+}
+
+// SetUserData sets the user-defined data fields from the tagged struct, see EncodeUserData.
+func (a *CreateAccount) SetUserData(v any) error {
+	data, err := EncodeUserData(v)
+	if err != nil {
+		return err
+	}
+	a.UserData128, a.UserData64, a.UserData32 = data.UserData128, data.UserData64, data.UserData32
+	return nil
+}
+
+// SetUserData sets the user-defined data fields from the tagged struct, see EncodeUserData.
+func (t *TransferData) SetUserData(v any) error {
+	data, err := EncodeUserData(v)
+	if err != nil {
+		return err
+	}
+	t.UserData128, t.UserData64, t.UserData32 = data.UserData128, data.UserData64, data.UserData32
+	return nil
+}
+
+// SetUserData sets the user-defined data filters from the tagged struct, see EncodeUserData.
+// Filters match whole fields and zero fields are disabled, so set every member packed into a filtered field.
+func (f *AccountTransferFilter) SetUserData(v any) error {
+	data, err := EncodeUserData(v)
+	if err != nil {
+		return err
+	}
+	f.UserData128, f.UserData64, f.UserData32 = data.UserData128, data.UserData64, data.UserData32
+	return nil
+}
+
+// DecodeUserData decodes the user-defined data fields into the tagged struct pointer, see DecodeUserData.
+func (a Account) DecodeUserData(v any) error {
+	return DecodeUserData(UserData{a.UserData128, a.UserData64, a.UserData32}, v)
+}
+
+// DecodeUserData decodes the user-defined data fields into the tagged struct pointer, see DecodeUserData.
+func (t AccountTransfer) DecodeUserData(v any) error {
+	return DecodeUserData(UserData{t.UserData128, t.UserData64, t.UserData32}, v)
+}
+
+// DecodeUserData decodes the user-defined data fields into the tagged struct pointer, see DecodeUserData.
+func (s AccountStatement) DecodeUserData(v any) error {
+	return DecodeUserData(UserData{s.UserData128, s.UserData64, s.UserData32}, v)
+}
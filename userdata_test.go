@@ -0,0 +1,120 @@
+package tbdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testChannel uint8
+
+type testPaymentMeta struct {
+	MerchantID uint64      `tbdb:"ud128,bits=0-63"`
+	Reference  uint64      `tbdb:"ud128,bits=64-127"`
+	Channel    testChannel `tbdb:"ud64,bits=0-7"`
+	Region     uint16      `tbdb:"ud64,bits=8-23"`
+	Batch      uint32      `tbdb:"ud32,bits=0-15"`
+	Refund     bool        `tbdb:"ud32,bits=31"`
+	Note       string
+}
+
+func TestUserDataRoundTrip(t *testing.T) {
+	meta := testPaymentMeta{
+		MerchantID: 42,
+		Reference:  7,
+		Channel:    3,
+		Region:     0xBEEF,
+		Batch:      0xFFFF,
+		Refund:     true,
+		Note:       "ignored",
+	}
+	data, err := EncodeUserData(&meta)
+	require.NoError(t, err)
+	assert.Equal(t, Uint128{Hi: 7, Lo: 42}, data.UserData128)
+	assert.Equal(t, uint64(0xBEEF03), data.UserData64)
+	assert.Equal(t, uint32(0x8000FFFF), data.UserData32)
+
+	var decoded testPaymentMeta
+	require.NoError(t, DecodeUserData(data, &decoded))
+	meta.Note = ""
+	assert.Equal(t, meta, decoded)
+
+	// Whole field.
+	type whole struct {
+		ID    Uint128 `tbdb:"ud128"`
+		Shard uint32  `tbdb:"ud64"`
+		Kind  uint32  `tbdb:"ud32"`
+	}
+	in := whole{ID: Uint128{Hi: 1, Lo: 2}, Shard: 0xFFFFFFFF, Kind: 9}
+	data, err = EncodeUserData(in)
+	require.NoError(t, err)
+	assert.Equal(t, UserData{UserData128: in.ID, UserData64: 0xFFFFFFFF, UserData32: 9}, data)
+	var out whole
+	require.NoError(t, DecodeUserData(data, &out))
+	assert.Equal(t, in, out)
+}
+
+func TestUserDataErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		v    any
+		err  error
+	}{
+		{"overflow", testPaymentMeta{Batch: 0x10000}, ErrUserDataOverflow},
+		{"not struct", 10, ErrInvalidUserDataTag},
+		{"unknown field", struct {
+			A uint8 `tbdb:"ud16"`
+		}{}, ErrInvalidUserDataTag},
+		{"unsupported type", struct {
+			A int64 `tbdb:"ud64"`
+		}{}, ErrInvalidUserDataTag},
+		{"exceeds field", struct {
+			A uint16 `tbdb:"ud32,bits=20-35"`
+		}{}, ErrInvalidUserDataTag},
+		{"exceeds type", struct {
+			A uint8 `tbdb:"ud32,bits=0-8"`
+		}{}, ErrInvalidUserDataTag},
+		{"overlap", struct {
+			A uint8 `tbdb:"ud32,bits=0-7"`
+			B uint8 `tbdb:"ud32,bits=7-14"`
+		}{}, ErrInvalidUserDataTag},
+		{"invalid bits", struct {
+			A uint8 `tbdb:"ud32,bits=7-0"`
+		}{}, ErrInvalidUserDataTag},
+		{"invalid option", struct {
+			A uint8 `tbdb:"ud32,size=8"`
+		}{}, ErrInvalidUserDataTag},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := EncodeUserData(tt.v)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	assert.ErrorIs(t, DecodeUserData(UserData{}, testPaymentMeta{}), ErrInvalidUserDataTag)
+}
+
+func TestUserDataIntegration(t *testing.T) {
+	meta := testPaymentMeta{MerchantID: 42, Channel: 3, Region: 1}
+
+	var account CreateAccount
+	require.NoError(t, account.SetUserData(meta))
+	var transfer TransferData
+	require.NoError(t, transfer.SetUserData(meta))
+	assert.Equal(t, account.UserData64, transfer.UserData64)
+
+	filter := AccountTransferFilter{}
+	require.NoError(t, filter.SetUserData(meta))
+	assert.Equal(t, Uint128FromUint64(42), filter.UserData128)
+	assert.Equal(t, uint64(0x103), filter.UserData64)
+	assert.Zero(t, filter.UserData32)
+
+	var decoded testPaymentMeta
+	require.NoError(t, AccountTransfer{UserData128: transfer.UserData128, UserData64: transfer.UserData64}.
+		DecodeUserData(&decoded))
+	assert.Equal(t, meta, decoded)
+	require.NoError(t, Account{UserData64: account.UserData64}.DecodeUserData(&decoded))
+	assert.Equal(t, testChannel(3), decoded.Channel)
+}
@@ -0,0 +1,214 @@
+package tbdb
+
+import (
+	"fmt"
+
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+// WalletRole defines the role of a wallet account.
+type WalletRole uint8
+
+// Enum of wallet role.
+const (
+	// Wallet account that holds the customer spendable balance.
+	WalletRoleBalance WalletRole = iota + 1
+	// Wallet account that holds funds on hold, e.g. pending withdrawals.
+	WalletRoleHold
+	// Wallet account that holds the customer reward points or cashback.
+	WalletRoleRewards
+)
+
+// String implements fmt.Stringer.
+func (r WalletRole) String() string {
+	switch r {
+	case WalletRoleBalance:
+		return "balance"
+	case WalletRoleHold:
+		return "hold"
+	case WalletRoleRewards:
+		return "rewards"
+	}
+	return fmt.Sprintf("WalletRole(%d)", uint8(r))
+}
+
+// ensureWalletAttempts is the number of attempts to create the missing accounts of a wallet,
+// a linked group fails if another process creates one of its accounts concurrently.
+const ensureWalletAttempts = 3
+
+// WalletTemplate defines the accounts of a customer wallet per currency.
+// Wallet accounts have UserData128 = customer ID and UserData32 = role.
+type WalletTemplate struct {
+	// Namespace scopes the deterministic account IDs; required.
+	Namespace Uint128
+	// Roles is the wallet account roles; empty is WalletRoleBalance & WalletRoleHold.
+	Roles []WalletRole
+	// Categories overrides the account category of a role; AccountCategoryBalance by default.
+	Categories map[WalletRole]AccountCategory
+}
+
+// roles returns the template roles, the defaults if empty.
+func (t WalletTemplate) roles() []WalletRole {
+	if len(t.Roles) == 0 {
+		return []WalletRole{WalletRoleBalance, WalletRoleHold}
+	}
+	return t.Roles
+}
+
+// category returns the account category of the role.
+func (t WalletTemplate) category(role WalletRole) AccountCategory {
+	if category, ok := t.Categories[role]; ok {
+		return category
+	}
+	return AccountCategoryBalance
+}
+
+// AccountID returns the deterministic ID of the customer wallet account of the role on the ledger.
+//
+//	balanceID := template.AccountID(customerID, tbdb.WalletRoleBalance, tbdb.IDR)
+func (t WalletTemplate) AccountID(customerID Uint128, role WalletRole, ledger Ledger) Uint128 {
+	return DeriveID(t.Namespace, fmt.Sprintf("wallet:%s:%s:%d", customerID.String(), role, ledger.EncodeLedger()))
+}
+
+// Wallet is the handle of the accounts of a customer wallet.
+type Wallet struct {
+	customerID Uint128
+	accounts   map[walletKey]Uint128
+	created    int
+}
+
+// walletKey defines the wallet account key.
+type walletKey struct {
+	role WalletRole
+	code string
+}
+
+// CustomerID returns the wallet customer ID.
+func (w *Wallet) CustomerID() Uint128 { return w.customerID }
+
+// Account returns the account ID of the role & currency, false if the wallet has no such account.
+func (w *Wallet) Account(role WalletRole, cur *Currency) (Uint128, bool) {
+	id, ok := w.accounts[walletKey{role, cur.Code()}]
+	return id, ok
+}
+
+// Balance returns the balance account ID of the currency.
+func (w *Wallet) Balance(cur *Currency) (Uint128, bool) { return w.Account(WalletRoleBalance, cur) }
+
+// Hold returns the hold account ID of the currency.
+func (w *Wallet) Hold(cur *Currency) (Uint128, bool) { return w.Account(WalletRoleHold, cur) }
+
+// Rewards returns the rewards account ID of the currency.
+func (w *Wallet) Rewards(cur *Currency) (Uint128, bool) { return w.Account(WalletRoleRewards, cur) }
+
+// Created returns the number of accounts created by EnsureWallet, zero if the wallet already existed.
+func (w *Wallet) Created() int { return w.created }
+
+// EnsureWallet creates the missing accounts of the customer wallet for every currency & template role.
+// Account IDs are deterministic, missing accounts are created atomically as a linked group and existing
+// accounts are accepted, so it is safe to call on every customer request.
+// Returns ErrWalletMismatch if an existing account has another ledger, code, flags, customer or role.
+func (i *Instance) EnsureWallet(customerID Uint128, currencies []*Currency, template WalletTemplate) (*Wallet, error) {
+	// Validate.
+	switch {
+	case customerID.IsZero():
+		return nil, fmt.Errorf("%w: customer id must not be zero", ErrInvalidWallet)
+	case len(currencies) == 0:
+		return nil, fmt.Errorf("%w: at least give 1 currency", ErrInvalidWallet)
+	case template.Namespace.IsZero():
+		return nil, fmt.Errorf("%w: template namespace must not be zero", ErrInvalidWallet)
+	}
+	cln, err := i.Client()
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the wallet accounts.
+	wallet := &Wallet{customerID: customerID, accounts: make(map[walletKey]Uint128)}
+	var accounts []CreateAccounts
+	for _, cur := range currencies {
+		if cur == nil {
+			return nil, fmt.Errorf("%w: currency must not be nil", ErrInvalidWallet)
+		}
+		for _, role := range template.roles() {
+			key := walletKey{role, cur.Code()}
+			if _, ok := wallet.accounts[key]; ok {
+				return nil, fmt.Errorf("%w: duplicate %s account of %s", ErrInvalidWallet, role, cur.Code())
+			}
+			category := template.category(role)
+			flags, err := category.Flags()
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s account: %w", ErrInvalidWallet, role, err)
+			}
+			id := template.AccountID(customerID, role, cur)
+			wallet.accounts[key] = id
+			accounts = append(accounts, CreateAccounts{
+				CreateAccount: CreateAccount{ID: id, UserData128: customerID, UserData32: uint32(role), Ledger: cur},
+				Code:          uint16(category),
+				Flags:         flags,
+			})
+		}
+	}
+	if len(accounts) > int(TigerBeetleMaxBatch) {
+		return nil, ErrExceedsMaxTigerBeetleBatch
+	}
+
+	for attempt := 0; attempt < ensureWalletAttempts; attempt++ {
+		// Check existing accounts.
+		ids := make([]types.Uint128, 0, len(accounts))
+		for _, account := range accounts {
+			ids = append(ids, toBinding(account.ID))
+		}
+		existing, err := cln.LookupAccounts(ids)
+		if err != nil {
+			return nil, err
+		}
+		found := make(map[Uint128]types.Account, len(existing))
+		for _, account := range existing {
+			found[fromBinding(account.ID)] = account
+		}
+		missing := make([]CreateAccounts, 0, len(accounts))
+		for _, account := range accounts {
+			tbAccount, ok := found[account.ID]
+			if !ok {
+				missing = append(missing, account)
+				continue
+			}
+			// The linked flag only chains the creation.
+			flags := tbAccount.AccountFlags()
+			flags.Linked = false
+			if tbAccount.Ledger != uint32(account.Ledger.EncodeLedger()) || tbAccount.Code != account.Code ||
+				flags.ToUint16() != account.Flags.ToUint16() || fromBinding(tbAccount.UserData128) != customerID ||
+				tbAccount.UserData32 != account.UserData32 {
+				return nil, fmt.Errorf("%w: account %s", ErrWalletMismatch, account.ID.String())
+			}
+		}
+		if len(missing) == 0 {
+			return wallet, nil
+		}
+
+		// Create the missing accounts as a linked group.
+		for idx := range missing {
+			missing[idx].Flags.Linked = idx < len(missing)-1
+		}
+		result, err := i.CreateAccountBatch(missing)
+		if err != nil {
+			return nil, err
+		}
+		raced := false
+		for _, r := range result.Results {
+			switch r.Result {
+			case types.AccountOK:
+			case types.AccountExists, types.AccountLinkedEventFailed:
+				raced = true
+			default:
+				return nil, fmt.Errorf("create wallet account %s: %w", r.ID.String(), r.Err)
+			}
+		}
+		if !raced {
+			wallet.created = len(missing)
+			return wallet, nil
+		}
+	}
+	return nil, fmt.Errorf("ensure wallet of %s: accounts are created concurrently, retry", customerID.String())
+}
@@ -0,0 +1,110 @@
+package tbdb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
+)
+
+func TestEnsureWallet(t *testing.T) {
+	client := &fakeClient{}
+	i := &Instance{client: client}
+	customerID := Uint128FromUint64(7)
+	template := WalletTemplate{
+		Namespace:  Uint128FromUint64(42),
+		Roles:      []WalletRole{WalletRoleBalance, WalletRoleHold, WalletRoleRewards},
+		Categories: map[WalletRole]AccountCategory{WalletRoleHold: AccountCategoryLiabilities},
+	}
+
+	wallet, err := i.EnsureWallet(customerID, []*Currency{IDR, USD}, template)
+	assert.NoError(t, err)
+	assert.Equal(t, 6, wallet.Created())
+	assert.Len(t, client.accounts, 6)
+
+	balanceID, ok := wallet.Balance(IDR)
+	assert.True(t, ok)
+	assert.Equal(t, template.AccountID(customerID, WalletRoleBalance, IDR), balanceID)
+	holdID, _ := wallet.Hold(USD)
+	hold, _ := client.account(toBinding(holdID))
+	assert.Equal(t, uint16(AccountCategoryLiabilities), hold.Code)
+	assert.Equal(t, customerID, fromBinding(hold.UserData128))
+	assert.Equal(t, uint32(WalletRoleHold), hold.UserData32)
+	_, ok = wallet.Rewards(EUR)
+	assert.False(t, ok)
+
+	// Ensuring again creates nothing.
+	wallet, err = i.EnsureWallet(customerID, []*Currency{IDR, USD}, template)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, wallet.Created())
+	assert.Equal(t, 1, client.calls)
+
+	// New currency only creates the missing accounts.
+	wallet, err = i.EnsureWallet(customerID, []*Currency{IDR, USD, EUR}, template)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, wallet.Created())
+}
+
+func TestEnsureWalletConcurrent(t *testing.T) {
+	client := &fakeClient{}
+	i := &Instance{client: client}
+	customerID := Uint128FromUint64(7)
+	template := WalletTemplate{Namespace: Uint128FromUint64(42)}
+
+	// Another process creates the hold account between lookup & create.
+	client.race = []types.Account{{
+		ID:          toBinding(template.AccountID(customerID, WalletRoleHold, IDR)),
+		UserData128: toBinding(customerID),
+		UserData32:  uint32(WalletRoleHold),
+		Ledger:      uint32(IDR.EncodeLedger()),
+		Code:        uint16(AccountCategoryBalance),
+		Flags:       types.AccountFlags{History: true, DebitsMustNotExceedCredits: true}.ToUint16(),
+	}}
+	wallet, err := i.EnsureWallet(customerID, []*Currency{IDR}, template)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, wallet.Created(), "linked group must be retried with the missing accounts")
+	assert.Equal(t, 2, client.calls)
+	assert.Len(t, client.accounts, 2)
+
+	_, err = i.EnsureWallet(Uint128{}, []*Currency{USD}, template)
+	assert.ErrorIs(t, err, ErrInvalidWallet)
+	_, err = i.EnsureWallet(customerID, []*Currency{USD}, WalletTemplate{})
+	assert.ErrorIs(t, err, ErrInvalidWallet)
+}
+
+func TestEnsureWalletMismatch(t *testing.T) {
+	customerID := Uint128FromUint64(8)
+	template := WalletTemplate{Namespace: Uint128FromUint64(42), Roles: []WalletRole{WalletRoleBalance}}
+	existing := types.Account{
+		ID:          toBinding(template.AccountID(customerID, WalletRoleBalance, USD)),
+		UserData128: toBinding(customerID),
+		UserData32:  uint32(WalletRoleBalance),
+		Ledger:      uint32(USD.EncodeLedger()),
+		Code:        uint16(AccountCategoryBalance),
+		Flags:       types.AccountFlags{Linked: true, History: true, DebitsMustNotExceedCredits: true}.ToUint16(),
+	}
+
+	tests := []struct {
+		name    string
+		modify  func(*types.Account)
+		wantErr error
+	}{
+		{"same account", nil, nil},
+		{"other customer", func(a *types.Account) { a.UserData128 = toBinding(Uint128FromUint64(9)) }, ErrWalletMismatch},
+		{"other role", func(a *types.Account) { a.UserData32 = uint32(WalletRoleHold) }, ErrWalletMismatch},
+		{"other flags", func(a *types.Account) { a.Flags = types.AccountFlags{History: true}.ToUint16() }, ErrWalletMismatch},
+		{"other code", func(a *types.Account) { a.Code = uint16(AccountCategoryControl) }, ErrWalletMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeClient{accounts: []types.Account{modified(existing, tt.modify)}}
+			wallet, err := (&Instance{client: client}).EnsureWallet(customerID, []*Currency{USD}, template)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Zero(t, wallet.Created())
+		})
+	}
+}
@@ -3,10 +3,11 @@ package tbdb
 import (
 	"encoding/binary"
 	"encoding/hex"
-	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"math/big"
+	"math/bits"
 
 	"github.com/tigerbeetle/tigerbeetle-go/pkg/types"
 )
@@ -50,6 +51,16 @@ func Uint128FromBytesLE(b []byte) (Uint128, error) {
 	return Uint128{Hi: hi, Lo: lo}, nil
 }
 
+// Uint128FromBytesBE constructs Uint128 from 16-byte big-endian slice.
+func Uint128FromBytesBE(b []byte) (Uint128, error) {
+	if len(b) != 16 {
+		return Uint128{}, fmt.Errorf("%w: want 16, got %d", ErrInvalidLength, len(b))
+	}
+	hi := binary.BigEndian.Uint64(b[0:8])
+	lo := binary.BigEndian.Uint64(b[8:16])
+	return Uint128{Hi: hi, Lo: lo}, nil
+}
+
 // Uint128FromHex decodes a hex string (big-endian, up to 32 chars) into Uint128.
 func Uint128FromHex(s string) (Uint128, error) {
 	if len(s) == 0 {
@@ -101,6 +112,14 @@ func (u Uint128) BytesLE() [16]byte {
 	return b
 }
 
+// BytesBE returns 16-byte big-endian representation.
+func (u Uint128) BytesBE() [16]byte {
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[0:8], u.Hi)
+	binary.BigEndian.PutUint64(b[8:16], u.Lo)
+	return b
+}
+
 // Hex returns big-endian hex string without leading zeros.
 func (u Uint128) Hex() string {
 	// Big-endian 16 bytes.
@@ -149,15 +168,14 @@ func (u *Uint128) UnmarshalText(b []byte) error {
 }
 
 // MarshalJSON implements json.Marshaler as string to avoid precision issues.
-func (u Uint128) MarshalJSON() ([]byte, error) { return json.Marshal(u.Hex()) }
+// The string is hex unless the package encoding is switched with SetUint128JSONEncoding.
+func (u Uint128) MarshalJSON() ([]byte, error) {
+	return marshalUint128JSON(u, Uint128JSONEncoding())
+}
 
-// UnmarshalJSON implements json.Unmarshaler.
+// UnmarshalJSON implements json.Unmarshaler with the package encoding of SetUint128JSONEncoding.
 func (u *Uint128) UnmarshalJSON(b []byte) error {
-	var s string
-	if err := json.Unmarshal(b, &s); err != nil {
-		return err
-	}
-	return u.UnmarshalText([]byte(s))
+	return unmarshalUint128JSON(u, b, Uint128JSONEncoding())
 }
 
 // IsZero returns true if Uint128 is zero.
@@ -180,6 +198,141 @@ func (u Uint128) Compare(v Uint128) int {
 	return 0
 }
 
+// === Begin Native Arithmetic ===
+
+// Add returns u+v, reporting whether the result overflows 128 bits.
+func (u Uint128) Add(v Uint128) (sum Uint128, overflow bool) {
+	lo, carry := bits.Add64(u.Lo, v.Lo, 0)
+	hi, carry := bits.Add64(u.Hi, v.Hi, carry)
+	return Uint128{Hi: hi, Lo: lo}, carry != 0
+}
+
+// Sub returns u-v, reporting whether the result underflows.
+func (u Uint128) Sub(v Uint128) (diff Uint128, underflow bool) {
+	lo, borrow := bits.Sub64(u.Lo, v.Lo, 0)
+	hi, borrow := bits.Sub64(u.Hi, v.Hi, borrow)
+	return Uint128{Hi: hi, Lo: lo}, borrow != 0
+}
+
+// Mul returns u*v, reporting whether the result overflows 128 bits.
+func (u Uint128) Mul(v Uint128) (product Uint128, overflow bool) {
+	if u.Hi != 0 && v.Hi != 0 {
+		return Uint128{}, true
+	}
+	hi, lo := bits.Mul64(u.Lo, v.Lo)
+	crossHi1, cross1 := bits.Mul64(u.Hi, v.Lo)
+	crossHi2, cross2 := bits.Mul64(u.Lo, v.Hi)
+	hi, carry1 := bits.Add64(hi, cross1, 0)
+	hi, carry2 := bits.Add64(hi, cross2, 0)
+	overflow = crossHi1 != 0 || crossHi2 != 0 || carry1 != 0 || carry2 != 0
+	return Uint128{Hi: hi, Lo: lo}, overflow
+}
+
+// QuoRem returns the quotient and remainder of u divided by v.
+// It panics if v is zero, like the built-in integer division.
+func (u Uint128) QuoRem(v Uint128) (quo, rem Uint128) {
+	if v.Hi == 0 {
+		q, r := u.QuoRem64(v.Lo)
+		return q, Uint128FromUint64(r)
+	}
+
+	// Estimate the quotient from the normalized divisor, it is either exact or one too small.
+	n := uint(bits.LeadingZeros64(v.Hi))
+	v1 := v.Lsh(n)
+	u1 := u.Rsh(1)
+	tq, _ := bits.Div64(u1.Hi, u1.Lo, v1.Hi)
+	tq >>= 63 - n
+	if tq != 0 {
+		tq--
+	}
+	quo = Uint128FromUint64(tq)
+	product, _ := v.Mul(quo)
+	rem, _ = u.Sub(product)
+	if rem.Compare(v) >= 0 {
+		quo, _ = quo.Add(Uint128FromUint64(1))
+		rem, _ = rem.Sub(v)
+	}
+	return quo, rem
+}
+
+// QuoRem64 returns the quotient and remainder of u divided by v.
+// It panics if v is zero, like the built-in integer division.
+func (u Uint128) QuoRem64(v uint64) (quo Uint128, rem uint64) {
+	hi, rem := bits.Div64(0, u.Hi, v)
+	lo, rem := bits.Div64(rem, u.Lo, v)
+	return Uint128{Hi: hi, Lo: lo}, rem
+}
+
+// Lsh returns u<<n, bits shifted beyond 128 bits are discarded.
+func (u Uint128) Lsh(n uint) Uint128 {
+	switch {
+	case n >= 128:
+		return Uint128{}
+	case n >= 64:
+		return Uint128{Hi: u.Lo << (n - 64)}
+	case n == 0:
+		return u
+	}
+	return Uint128{Hi: u.Hi<<n | u.Lo>>(64-n), Lo: u.Lo << n}
+}
+
+// Rsh returns u>>n.
+func (u Uint128) Rsh(n uint) Uint128 {
+	switch {
+	case n >= 128:
+		return Uint128{}
+	case n >= 64:
+		return Uint128{Lo: u.Hi >> (n - 64)}
+	case n == 0:
+		return u
+	}
+	return Uint128{Hi: u.Hi >> n, Lo: u.Lo>>n | u.Hi<<(64-n)}
+}
+
+// BitLen returns the minimum number of bits required to represent u, zero for zero.
+func (u Uint128) BitLen() int {
+	if u.Hi != 0 {
+		return 64 + bits.Len64(u.Hi)
+	}
+	return bits.Len64(u.Lo)
+}
+
+// IsUint64 returns true if u fits in uint64.
+func (u Uint128) IsUint64() bool { return u.Hi == 0 }
+
+// Float64 returns u as float64, precision beyond 53 bits is lost.
+func (u Uint128) Float64() float64 {
+	if u.Hi == 0 {
+		return float64(u.Lo)
+	}
+	return float64(u.Hi)*(1<<64) + float64(u.Lo)
+}
+
+// pow10Uint128 holds the powers of ten that fit in Uint128, 10^0 to 10^38.
+var pow10Uint128 = func() (pow [39]Uint128) {
+	pow[0] = Uint128FromUint64(1)
+	for n := 1; n < len(pow); n++ {
+		pow[n], _ = pow[n-1].Mul(Uint128FromUint64(10))
+	}
+	return pow
+}()
+
+// uint128FromFloat64 converts a non-negative integral float64 to Uint128,
+// false if f is negative, NaN or does not fit in Uint128.
+func uint128FromFloat64(f float64) (Uint128, bool) {
+	switch {
+	case math.IsNaN(f) || f < 0 || f >= 1<<128:
+		return Uint128{}, false
+	case f < 1<<64:
+		return Uint128FromUint64(uint64(f)), true
+	}
+	// f has at most 53 significant bits, so both halves are exact.
+	hi := math.Floor(f / (1 << 64))
+	return Uint128{Hi: uint64(hi), Lo: uint64(f - hi*(1<<64))}, true
+}
+
+// === End Native Arithmetic ===
+
 func toBinding(u Uint128) types.Uint128 {
 	le := u.BytesLE()
 	return types.BytesToUint128(le)
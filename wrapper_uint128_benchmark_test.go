@@ -0,0 +1,38 @@
+package tbdb
+
+import "testing"
+
+func BenchmarkAmountCurrency_Add(b *testing.B) {
+	amount := IDR.NewAmountFromMinorUnits(Uint128{Hi: 1, Lo: 2_000_050})
+	other := Uint128FromUint64(1_000)
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = amount.Add(other)
+	}
+}
+
+func BenchmarkAmountCurrency_Compare(b *testing.B) {
+	amount := IDR.NewAmountFromMinorUnits(Uint128{Hi: 1, Lo: 2_000_050})
+	other := Uint128FromUint64(1_000)
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = amount.Compare(other)
+	}
+}
+
+func BenchmarkAmountCurrency_Uint128ToFloat64(b *testing.B) {
+	amount := IDR.NewAmountFromMinorUnits(Uint128{Hi: 1, Lo: 2_000_050})
+	b.ReportAllocs()
+	for b.Loop() {
+		_ = amount.Uint128ToFloat64()
+	}
+}
+
+func BenchmarkUint128_QuoRem(b *testing.B) {
+	u := Uint128{Hi: 0x198bbe6eb9b35ae, Lo: 0x66392201c924e50}
+	v := Uint128{Hi: 3, Lo: 7}
+	b.ReportAllocs()
+	for b.Loop() {
+		_, _ = u.QuoRem(v)
+	}
+}
@@ -0,0 +1,186 @@
+package tbdb
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+)
+
+// Uint128Encoding defines the textual encoding of Uint128 in JSON.
+type Uint128Encoding uint8
+
+// Enum of Uint128 encoding.
+const (
+	// Uint128EncodingHex encodes as big-endian hex string without leading zeros, e.g. "1e8480".
+	Uint128EncodingHex Uint128Encoding = iota
+	// Uint128EncodingDecimal encodes as base-10 string, e.g. "2000000".
+	Uint128EncodingDecimal
+)
+
+// uint128JSONEncoding is the package-wide JSON encoding of Uint128.
+var uint128JSONEncoding atomic.Uint32
+
+// SetUint128JSONEncoding switches the JSON encoding of every Uint128 value, the default is Uint128EncodingHex.
+// Use Uint128Hex or Uint128Decimal to pin the encoding of a single field.
+func SetUint128JSONEncoding(enc Uint128Encoding) error {
+	if enc != Uint128EncodingHex && enc != Uint128EncodingDecimal {
+		return ErrUnknownEncoding
+	}
+	uint128JSONEncoding.Store(uint32(enc))
+	return nil
+}
+
+// Uint128JSONEncoding returns the package-wide JSON encoding of Uint128.
+func Uint128JSONEncoding() Uint128Encoding { return Uint128Encoding(uint128JSONEncoding.Load()) }
+
+// ParseUint128Decimal parses a base-10 string of digits into Uint128, e.g. "340282366920938463463374607431768211455".
+func ParseUint128Decimal(s string) (Uint128, error) {
+	if len(s) == 0 {
+		return Uint128{}, fmt.Errorf("parse uint128 %q: %w", s, ErrInvalidDecimal)
+	}
+	var val Uint128
+	for idx := 0; idx < len(s); idx++ {
+		digit := s[idx]
+		if digit < '0' || digit > '9' {
+			return Uint128{}, fmt.Errorf("parse uint128 %q: %w", s, ErrInvalidDecimal)
+		}
+		var overflow bool
+		if val, overflow = mulAdd64(val, 10, uint64(digit-'0')); overflow {
+			return Uint128{}, fmt.Errorf("parse uint128 %q: %w", s, ErrUint128Overflow)
+		}
+	}
+	return val, nil
+}
+
+// DecimalString returns base-10 string without leading zeros.
+func (u Uint128) DecimalString() string { return decimalDigits(u) }
+
+// MarshalBinary implements encoding.BinaryMarshaler as 16-byte big-endian, so the bytes sort like the value.
+func (u Uint128) MarshalBinary() ([]byte, error) {
+	b := u.BytesBE()
+	return b[:], nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler from 16-byte big-endian.
+func (u *Uint128) UnmarshalBinary(b []byte) error {
+	v, err := Uint128FromBytesBE(b)
+	if err != nil {
+		return err
+	}
+	*u = v
+	return nil
+}
+
+// Value implements driver.Valuer as base-10 string, suitable for NUMERIC(39, 0) columns.
+func (u Uint128) Value() (driver.Value, error) { return u.DecimalString(), nil }
+
+// Scan implements sql.Scanner from NUMERIC columns, scanned as base-10 string, bytes or int64.
+// NULL scans into zero.
+func (u *Uint128) Scan(src any) error {
+	var (
+		v   Uint128
+		err error
+	)
+	switch src := src.(type) {
+	case nil:
+	case string:
+		v, err = ParseUint128Decimal(src)
+	case []byte:
+		v, err = ParseUint128Decimal(string(src))
+	case int64:
+		if src < 0 {
+			return ErrNegativeAmount
+		}
+		v = Uint128FromUint64(uint64(src))
+	default:
+		return fmt.Errorf("%w: %T", ErrUnsupportedScan, src)
+	}
+	if err != nil {
+		return err
+	}
+	*u = v
+	return nil
+}
+
+// Uint128Hex is Uint128 that always encodes in JSON as hex string, regardless of SetUint128JSONEncoding.
+type Uint128Hex Uint128
+
+// MarshalJSON implements json.Marshaler.
+func (u Uint128Hex) MarshalJSON() ([]byte, error) {
+	return marshalUint128JSON(Uint128(u), Uint128EncodingHex)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (u *Uint128Hex) UnmarshalJSON(b []byte) error {
+	return unmarshalUint128JSON((*Uint128)(u), b, Uint128EncodingHex)
+}
+
+// Uint128Decimal is Uint128 that always encodes in JSON as base-10 string, regardless of SetUint128JSONEncoding.
+type Uint128Decimal Uint128
+
+// MarshalJSON implements json.Marshaler.
+func (u Uint128Decimal) MarshalJSON() ([]byte, error) {
+	return marshalUint128JSON(Uint128(u), Uint128EncodingDecimal)
+}
+
+// UnmarshalJSON implements json.Unmarshaler, a bare JSON number is accepted as well.
+func (u *Uint128Decimal) UnmarshalJSON(b []byte) error {
+	return unmarshalUint128JSON((*Uint128)(u), b, Uint128EncodingDecimal)
+}
+
+// String implements fmt.Stringer as base-10 string.
+func (u Uint128Decimal) String() string { return Uint128(u).DecimalString() }
+
+// Uint128Blob is Uint128 stored in SQL as 16-byte big-endian blob, e.g. BYTEA or BINARY(16) columns.
+type Uint128Blob Uint128
+
+// Value implements driver.Valuer.
+func (u Uint128Blob) Value() (driver.Value, error) { return Uint128(u).MarshalBinary() }
+
+// Scan implements sql.Scanner, NULL scans into zero.
+func (u *Uint128Blob) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*u = Uint128Blob{}
+		return nil
+	case []byte:
+		return (*Uint128)(u).UnmarshalBinary(src)
+	default:
+		return fmt.Errorf("%w: %T", ErrUnsupportedScan, src)
+	}
+}
+
+func marshalUint128JSON(u Uint128, enc Uint128Encoding) ([]byte, error) {
+	if enc == Uint128EncodingDecimal {
+		return strconv.AppendQuote(nil, u.DecimalString()), nil
+	}
+	return json.Marshal(u.Hex())
+}
+
+func unmarshalUint128JSON(u *Uint128, b []byte, enc Uint128Encoding) error {
+	// Bare JSON number, only valid as decimal.
+	if enc == Uint128EncodingDecimal && len(b) > 0 && b[0] != '"' && string(b) != "null" {
+		v, err := ParseUint128Decimal(string(b))
+		if err != nil {
+			return err
+		}
+		*u = v
+		return nil
+	}
+
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	if enc == Uint128EncodingDecimal {
+		v, err := ParseUint128Decimal(s)
+		if err != nil {
+			return err
+		}
+		*u = v
+		return nil
+	}
+	return u.UnmarshalText([]byte(s))
+}
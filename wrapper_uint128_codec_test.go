@@ -0,0 +1,125 @@
+package tbdb
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUint128Decimal(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Uint128
+		wantErr error
+	}{
+		{"zero", "0", Uint128{}, nil},
+		{"uint64", "2000000", Uint128FromUint64(2_000_000), nil},
+		{"max", "340282366920938463463374607431768211455", Uint128{Hi: ^uint64(0), Lo: ^uint64(0)}, nil},
+		{"overflow", "340282366920938463463374607431768211456", Uint128{}, ErrUint128Overflow},
+		{"empty", "", Uint128{}, ErrInvalidDecimal},
+		{"sign", "+1", Uint128{}, ErrInvalidDecimal},
+		{"fraction", "1.0", Uint128{}, ErrInvalidDecimal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUint128Decimal(tt.input)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr, "ParseUint128Decimal(%q) must return error", tt.input)
+				return
+			}
+			assert.NoError(t, err, "Unexpected error for input %q", tt.input)
+			assert.Equal(t, tt.want, got, "ParseUint128Decimal(%q) returned unexpected result", tt.input)
+			assert.Equal(t, tt.input, got.DecimalString(), "DecimalString must round trip")
+		})
+	}
+}
+
+func TestUint128JSONEncoding(t *testing.T) {
+	type record struct {
+		ID     Uint128        `json:"id"`
+		Hex    Uint128Hex     `json:"hex"`
+		Amount Uint128Decimal `json:"amount"`
+	}
+	val := Uint128FromUint64(2_000_000)
+	in := record{ID: val, Hex: Uint128Hex(val), Amount: Uint128Decimal(val)}
+
+	b, err := json.Marshal(in)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":"1e8480","hex":"1e8480","amount":"2000000"}`, string(b))
+
+	assert.NoError(t, SetUint128JSONEncoding(Uint128EncodingDecimal))
+	defer func() { _ = SetUint128JSONEncoding(Uint128EncodingHex) }()
+	b, err = json.Marshal(in)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"id":"2000000","hex":"1e8480","amount":"2000000"}`, string(b))
+
+	var out record
+	assert.NoError(t, json.Unmarshal([]byte(`{"id":"2000000","hex":"1e8480","amount":2000000}`), &out))
+	assert.Equal(t, in, out, "Unmarshal returned unexpected result")
+
+	assert.ErrorIs(t, SetUint128JSONEncoding(Uint128Encoding(9)), ErrUnknownEncoding)
+}
+
+func TestUint128SQL(t *testing.T) {
+	val := Uint128{Hi: 1, Lo: 2}
+
+	v, err := val.Value()
+	assert.NoError(t, err)
+	assert.Equal(t, "18446744073709551618", v)
+
+	tests := []struct {
+		name    string
+		src     any
+		want    Uint128
+		wantErr error
+	}{
+		{"string", "18446744073709551618", val, nil},
+		{"bytes", []byte("18446744073709551618"), val, nil},
+		{"int64", int64(42), Uint128FromUint64(42), nil},
+		{"null", nil, Uint128{}, nil},
+		{"negative", int64(-1), Uint128{}, ErrNegativeAmount},
+		{"float", 1.5, Uint128{}, ErrUnsupportedScan},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Uint128
+			err := got.Scan(tt.src)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr, "Scan(%v) must return error", tt.src)
+				return
+			}
+			assert.NoError(t, err, "Unexpected error for source %v", tt.src)
+			assert.Equal(t, tt.want, got, "Scan(%v) returned unexpected result", tt.src)
+		})
+	}
+
+	blob, err := Uint128Blob(val).Value()
+	assert.NoError(t, err)
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 2}, blob)
+	var scanned Uint128Blob
+	assert.NoError(t, scanned.Scan(blob))
+	assert.Equal(t, val, Uint128(scanned))
+	assert.ErrorIs(t, scanned.Scan([]byte{1}), ErrInvalidLength)
+}
+
+func TestUint128Binary(t *testing.T) {
+	val := Uint128{Hi: 0x198bbe6eb9b35ae, Lo: 0x66392201c924e50}
+	b, err := val.MarshalBinary()
+	assert.NoError(t, err)
+	assert.Len(t, b, 16)
+
+	var got Uint128
+	assert.NoError(t, got.UnmarshalBinary(b))
+	assert.Equal(t, val, got, "UnmarshalBinary must round trip")
+	assert.ErrorIs(t, got.UnmarshalBinary(b[:8]), ErrInvalidLength)
+}
+
+func TestUint128DecimalString(t *testing.T) {
+	assert.Equal(t, "1000", Uint128Decimal(Uint128FromUint64(1000)).String())
+	assert.Equal(t, "1000", fmt.Sprint(Uint128Decimal(Uint128FromUint64(1000))))
+}
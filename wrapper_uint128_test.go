@@ -1,6 +1,7 @@
 package tbdb
 
 import (
+	"math"
 	"math/big"
 	"testing"
 
@@ -148,3 +149,114 @@ func TestUint128FromBigInt(t *testing.T) {
 		})
 	}
 }
+
+func TestUint128Arithmetic(t *testing.T) {
+	maxU := Uint128{Hi: ^uint64(0), Lo: ^uint64(0)}
+	tests := []struct {
+		name string
+		a    Uint128
+		b    Uint128
+	}{
+		{"small", Uint128FromUint64(1234), Uint128FromUint64(56)},
+		{"carry into hi", Uint128FromUint64(^uint64(0)), Uint128FromUint64(1)},
+		{"both hi", Uint128{Hi: 0x198bbe6eb9b35ae, Lo: 0x66392201c924e50}, Uint128{Hi: 3, Lo: 7}},
+		{"divisor with hi", Uint128{Hi: 1 << 63, Lo: 12345}, Uint128{Hi: 1, Lo: ^uint64(0)}},
+		{"divisor greater", Uint128FromUint64(5), Uint128{Hi: 1}},
+		{"max", maxU, maxU},
+		{"max by one", maxU, Uint128FromUint64(1)},
+	}
+
+	limit := new(big.Int).Lsh(big.NewInt(1), 128)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := tt.a.BigInt(), tt.b.BigInt()
+
+			sum, overflow := tt.a.Add(tt.b)
+			want := new(big.Int).Add(a, b)
+			assert.Equal(t, want.Cmp(limit) >= 0, overflow, "Add overflow")
+			if !overflow {
+				assert.Equal(t, 0, want.Cmp(sum.BigInt()), "Add returned unexpected result")
+			}
+
+			diff, underflow := tt.a.Sub(tt.b)
+			want = new(big.Int).Sub(a, b)
+			assert.Equal(t, want.Sign() < 0, underflow, "Sub underflow")
+			if !underflow {
+				assert.Equal(t, 0, want.Cmp(diff.BigInt()), "Sub returned unexpected result")
+			}
+
+			product, overflow := tt.a.Mul(tt.b)
+			want = new(big.Int).Mul(a, b)
+			assert.Equal(t, want.Cmp(limit) >= 0, overflow, "Mul overflow")
+			if !overflow {
+				assert.Equal(t, 0, want.Cmp(product.BigInt()), "Mul returned unexpected result")
+			}
+
+			quo, rem := tt.a.QuoRem(tt.b)
+			wantQuo, wantRem := new(big.Int).QuoRem(a, b, new(big.Int))
+			assert.Equal(t, 0, wantQuo.Cmp(quo.BigInt()), "QuoRem returned unexpected quotient")
+			assert.Equal(t, 0, wantRem.Cmp(rem.BigInt()), "QuoRem returned unexpected remainder")
+
+			assert.Equal(t, a.Cmp(b), tt.a.Compare(tt.b), "Compare returned unexpected result")
+			assert.Equal(t, a.BitLen(), tt.a.BitLen(), "BitLen returned unexpected result")
+		})
+	}
+}
+
+func TestUint128Shift(t *testing.T) {
+	u := Uint128{Hi: 0x0123456789abcdef, Lo: 0xfedcba9876543210}
+	for _, n := range []uint{0, 1, 4, 63, 64, 65, 100, 127, 128, 200} {
+		wantLsh := new(big.Int).Lsh(u.BigInt(), n)
+		wantLsh.And(wantLsh, new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1)))
+		assert.Equal(t, 0, wantLsh.Cmp(u.Lsh(n).BigInt()), "Lsh(%d) returned unexpected result", n)
+		wantRsh := new(big.Int).Rsh(u.BigInt(), n)
+		assert.Equal(t, 0, wantRsh.Cmp(u.Rsh(n).BigInt()), "Rsh(%d) returned unexpected result", n)
+	}
+}
+
+func TestPow10Uint128(t *testing.T) {
+	want := big.NewInt(1)
+	for n, got := range pow10Uint128 {
+		assert.Equal(t, 0, want.Cmp(got.BigInt()), "pow10Uint128[%d] returned unexpected result", n)
+		want.Mul(want, big.NewInt(10))
+	}
+}
+
+func TestUint128FromFloat64(t *testing.T) {
+	tests := []struct {
+		name   string
+		f      float64
+		want   Uint128
+		wantOk bool
+	}{
+		{"zero", 0, Uint128{}, true},
+		{"uint64", 12345, Uint128FromUint64(12345), true},
+		{"2^64", 1 << 64, Uint128{Hi: 1}, true},
+		{"2^100 + 2^60", 1<<100 + 1<<60, Uint128{Hi: 1 << 36, Lo: 1 << 60}, true},
+		{"2^128", 1 << 128, Uint128{}, false},
+		{"negative", -1, Uint128{}, false},
+		{"NaN", math.NaN(), Uint128{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := uint128FromFloat64(tt.f)
+			assert.Equal(t, tt.wantOk, ok, "uint128FromFloat64(%g) returned unexpected ok", tt.f)
+			assert.Equal(t, tt.want, got, "uint128FromFloat64(%g) returned unexpected result", tt.f)
+		})
+	}
+}
+
+func TestAmountArithmeticAllocations(t *testing.T) {
+	amount := IDR.NewAmountFromMinorUnits(Uint128{Hi: 1, Lo: 2_000_050})
+	other := Uint128FromUint64(1_000)
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = amount.Add(other)
+		_, _ = amount.Sub(other)
+		_ = amount.Compare(other)
+		_ = amount.IsZero()
+		_ = amount.Uint128ToFloat64()
+		_, _ = mulDiv(other, other, Uint128FromUint64(3), RoundingHalfEven)
+	})
+	assert.Zero(t, allocs, "amount arithmetic must not allocate")
+}